@@ -0,0 +1,128 @@
+package jsoncall
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// ErrCodecSplit is returned when decoding arguments with a codec which does not implement Splitter.
+var ErrCodecSplit error = newCallError(http.StatusInternalServerError, "codec_split", "Codec must implement Splitter to decode arguments")
+
+// Codec decodes encoded arguments. Unmarshal is passed the encoding of each
+// param with a pointer to its parameter type.
+type Codec interface {
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// Splitter is implemented by codecs which split an encoded params array into
+// the encoding of each param, such as a msgpack array into its elements. It
+// is required for decoding arguments with WithCodec, while codecs without it
+// may still decode single values with DecodeArgument.
+type Splitter interface {
+	Split(data []byte) ([][]byte, error)
+}
+
+// JSONCodec is the default codec.
+var JSONCodec Codec = jsonCodec{}
+
+// jsonCodec decodes JSON using encoding/json.
+type jsonCodec struct{}
+
+// Unmarshal implementation.
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// Split implementation.
+func (jsonCodec) Split(data []byte) ([][]byte, error) {
+	params, err := parseParams(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	raw := make([][]byte, len(params))
+	for i, p := range params {
+		raw[i] = p
+	}
+
+	return raw, nil
+}
+
+// WithCodec decodes arguments with the given codec rather than JSON, for
+// example msgpack for service-to-service calls. The codec must implement
+// Splitter to split the params array, otherwise ErrCodecSplit is returned.
+// Arity and context injection are unchanged, however options which inspect
+// the JSON text itself, such as WithSpreadSlice, WithTrailingKeywords,
+// WithRejectNull or WithMaxDepth, have no effect. Codec errors decoding a
+// param are wrapped in an *ArgumentError.
+func WithCodec(codec Codec) Option {
+	return func(v *config) {
+		v.codec = codec
+	}
+}
+
+// hasCodec returns true when a codec other than JSONCodec is used.
+func (c *config) hasCodec() bool {
+	return c.codec != nil && c.codec != JSONCodec
+}
+
+// codecArguments returns the arguments of a function decoded by the codec.
+func codecArguments(t reflect.Type, s string, c *config) ([]reflect.Value, error) {
+	if err := c.checkFunc(t); err != nil {
+		return nil, err
+	}
+
+	params, err := c.splitParams(s)
+	if err != nil {
+		return nil, err
+	}
+
+	types := c.params(t)
+	min, max := c.arityBounds(t, types, len(params))
+	params, err = c.checkArity(params, min, max)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.bind(t, func(i int) (json.RawMessage, bool) {
+		if i < len(params) {
+			return params[i], true
+		}
+		return nil, false
+	})
+}
+
+// splitParams returns the params of s split by the codec, blank input being
+// treated as no params as it is for JSON.
+func (c *config) splitParams(s string) ([]json.RawMessage, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+
+	sp, ok := c.codec.(Splitter)
+	if !ok {
+		return nil, ErrCodecSplit
+	}
+
+	raw, err := sp.Split([]byte(s))
+	if err != nil {
+		return nil, err
+	}
+
+	params := make([]json.RawMessage, len(raw))
+	for i, p := range raw {
+		params[i] = p
+	}
+
+	return params, nil
+}
+
+// codecDecode decodes the raw param at index i into value using the codec.
+func (c *config) codecDecode(i int, raw json.RawMessage, value interface{}) error {
+	if err := c.codec.Unmarshal(raw, value); err != nil {
+		return &ArgumentError{Index: i, Err: err}
+	}
+	return c.validate(i, value)
+}
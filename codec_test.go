@@ -0,0 +1,139 @@
+package jsoncall_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tj/assert"
+	jsoncall "github.com/tj/go-jsoncall"
+)
+
+// csvCodec decodes params from a CSV record, each field being a param
+// scanned into its parameter, or taken as is for strings.
+type csvCodec struct{}
+
+func (csvCodec) Split(data []byte) ([][]byte, error) {
+	record, err := csv.NewReader(bytes.NewReader(data)).Read()
+	if err != nil {
+		return nil, err
+	}
+
+	var params [][]byte
+	for _, field := range record {
+		params = append(params, []byte(field))
+	}
+	return params, nil
+}
+
+func (csvCodec) Unmarshal(data []byte, v interface{}) error {
+	if s, ok := v.(*string); ok {
+		*s = string(data)
+		return nil
+	}
+
+	_, err := fmt.Sscan(string(data), v)
+	return err
+}
+
+// gobCodec decodes single gob encoded values, without splitting params.
+type gobCodec struct{}
+
+func (gobCodec) Unmarshal(data []byte, v interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+// Test decoding arguments with a codec.
+func TestWithCodec(t *testing.T) {
+	codec := jsoncall.WithCodec(csvCodec{})
+
+	t.Run("should decode params with the codec", func(t *testing.T) {
+		v, err := jsoncall.CallFunc(add, "1,2", codec)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, v[0].Interface())
+
+		fn := func(ctx context.Context, name string, n int) string { return strings.Repeat(name, n) }
+		v, err = jsoncall.CallFunc(fn, `"Tobi, Loki",2`, codec)
+		assert.NoError(t, err)
+		assert.Equal(t, "Tobi, LokiTobi, Loki", v[0].Interface())
+	})
+
+	t.Run("should check arity", func(t *testing.T) {
+		_, err := jsoncall.CallFunc(add, "1", codec)
+		assert.EqualError(t, err, `Too few arguments: expected 2, got 1`)
+
+		_, err = jsoncall.CallFunc(add, "1,2,3", codec)
+		assert.EqualError(t, err, `Too many arguments: expected 2, got 3`)
+	})
+
+	t.Run("should wrap param errors", func(t *testing.T) {
+		_, err := jsoncall.CallFunc(add, "1,two", codec)
+		assert.Error(t, err)
+
+		var e *jsoncall.ArgumentError
+		assert.True(t, errors.As(err, &e))
+		assert.Equal(t, 1, e.Index)
+	})
+
+	t.Run("should treat blank input as no params", func(t *testing.T) {
+		for _, args := range []string{``, `  `, "\n\t"} {
+			v, err := jsoncall.CallFunc(func(ctx context.Context) int { return 1 }, args, codec)
+			assert.NoError(t, err, args)
+			assert.Equal(t, 1, v[0].Interface())
+
+			_, err = jsoncall.CallFunc(add, args, codec)
+			assert.EqualError(t, err, `Too few arguments: expected 2, got 0`)
+
+			n, err := jsoncall.Call0(func() int { return 1 }, args, codec)
+			assert.NoError(t, err, args)
+			assert.Equal(t, 1, n)
+		}
+	})
+
+	t.Run("should decode params of generic calls", func(t *testing.T) {
+		v, err := jsoncall.Call2(func(a, b int) int { return a + b }, "1,2", codec)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, v)
+
+		_, err = jsoncall.Call2(func(a, b int) int { return a + b }, "1", codec)
+		assert.EqualError(t, err, `Too few arguments: expected 2, got 1`)
+	})
+
+	t.Run("should return errors splitting params", func(t *testing.T) {
+		_, err := jsoncall.CallFunc(add, `"1,2`, codec)
+		assert.Error(t, err)
+	})
+
+	t.Run("should require codecs to split params", func(t *testing.T) {
+		_, err := jsoncall.CallFunc(add, "1,2", jsoncall.WithCodec(gobCodec{}))
+		assert.Equal(t, jsoncall.ErrCodecSplit, err)
+	})
+
+	t.Run("should decode JSON with JSONCodec", func(t *testing.T) {
+		v, err := jsoncall.CallFunc(add, `[1, 2]`, jsoncall.WithCodec(jsoncall.JSONCodec))
+		assert.NoError(t, err)
+		assert.Equal(t, 3, v[0].Interface())
+
+		_, err = jsoncall.CallFunc(add, `[1, "2"]`, jsoncall.WithCodec(jsoncall.JSONCodec))
+		assert.EqualError(t, err, `Incorrect type string, expected number`)
+
+		params, err := jsoncall.JSONCodec.(jsoncall.Splitter).Split([]byte(`[1, "two"]`))
+		assert.NoError(t, err)
+		assert.Equal(t, [][]byte{[]byte(`1`), []byte(`"two"`)}, params)
+	})
+
+	t.Run("should decode single arguments", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.NoError(t, gob.NewEncoder(&buf).Encode(User{Name: "Loki"}))
+
+		v, err := jsoncall.DecodeArgument(reflect.TypeOf(User{}), buf.Bytes(), jsoncall.WithCodec(gobCodec{}))
+		assert.NoError(t, err)
+		assert.Equal(t, User{Name: "Loki"}, v.Interface())
+	})
+}
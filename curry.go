@@ -0,0 +1,78 @@
+package jsoncall
+
+import (
+	"net/http"
+	"reflect"
+)
+
+// ErrNotFunctionResult is returned when a curried function does not return a function.
+var ErrNotFunctionResult error = newCallError(http.StatusInternalServerError, "not_function_result", "Function must return a function")
+
+// Caller invokes a function value with arguments derived from json strings,
+// such as the function returned by a curried call.
+type Caller struct {
+	fn reflect.Value
+}
+
+// CallFuncCurried invokes a function with arguments derived from a json
+// string, where the sole non-error result is itself a function, returning a
+// *Caller for it. This supports partial application, for example configuring
+// a plugin with func(Config) func(context.Context, Request) (Response, error)
+// and then handling each request with the returned function. The returned
+// function must be one which CheckFunc accepts.
+func CallFuncCurried(fn interface{}, args string, options ...Option) (*Caller, error) {
+	values, err := CallFunc(fn, args, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return curried(values)
+}
+
+// Call invokes the function with arguments derived from a json string, as
+// CallFunc does.
+func (c *Caller) Call(args string, options ...Option) ([]reflect.Value, error) {
+	return CallValue(c.fn, args, options...)
+}
+
+// CallCurried invokes the function as CallFuncCurried does, for chains of
+// curried functions.
+func (c *Caller) CallCurried(args string, options ...Option) (*Caller, error) {
+	values, err := c.Call(args, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return curried(values)
+}
+
+// Func returns the function invoked by the caller.
+func (c *Caller) Func() reflect.Value {
+	return c.fn
+}
+
+// curried returns a caller for the sole non-error result, which must be a
+// non-nil function.
+func curried(values []reflect.Value) (*Caller, error) {
+	var results []reflect.Value
+	for _, v := range values {
+		if !isError(v.Type()) {
+			results = append(results, v)
+		}
+	}
+
+	if len(results) != 1 {
+		return nil, ErrNotFunctionResult
+	}
+
+	fn := results[0]
+	if fn.Kind() != reflect.Func || fn.IsNil() {
+		return nil, ErrNotFunctionResult
+	}
+
+	if err := CheckFunc(fn.Interface()); err != nil {
+		return nil, err
+	}
+
+	return &Caller{fn: fn}, nil
+}
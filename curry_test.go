@@ -0,0 +1,110 @@
+package jsoncall_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tj/assert"
+	jsoncall "github.com/tj/go-jsoncall"
+)
+
+// GreeterConfig configures a greeter plugin.
+type GreeterConfig struct {
+	Greeting string `json:"greeting"`
+}
+
+// greeter returns a request handler configured by c.
+func greeter(c GreeterConfig) func(context.Context, string) (string, error) {
+	return func(ctx context.Context, name string) (string, error) {
+		if name == "" {
+			return "", errors.New("name required")
+		}
+		return c.Greeting + " " + name, nil
+	}
+}
+
+// Test calling of curried functions.
+func TestCallFuncCurried(t *testing.T) {
+	t.Run("should call the returned function", func(t *testing.T) {
+		c, err := jsoncall.CallFuncCurried(greeter, `[{ "greeting": "Hello" }]`)
+		assert.NoError(t, err)
+
+		v, err := c.Call(`["Tobi"]`)
+		assert.NoError(t, err)
+		assert.Equal(t, "Hello Tobi", v[0].Interface())
+
+		v, err = c.Call(`["Loki"]`)
+		assert.NoError(t, err)
+		assert.Equal(t, "Hello Loki", v[0].Interface())
+
+		_, err = c.Call(`[""]`)
+		assert.EqualError(t, err, `name required`)
+
+		_, err = c.Call(`[]`)
+		assert.EqualError(t, err, `Too few arguments: expected 1, got 0`)
+	})
+
+	t.Run("should support functions alongside errors", func(t *testing.T) {
+		fn := func(sep string) (func(parts ...string) string, error) {
+			if sep == "" {
+				return nil, errors.New("separator required")
+			}
+			return func(parts ...string) string { return strings.Join(parts, sep) }, nil
+		}
+
+		c, err := jsoncall.CallFuncCurried(fn, `["-"]`)
+		assert.NoError(t, err)
+
+		v, err := c.Call(`["a", "b"]`)
+		assert.NoError(t, err)
+		assert.Equal(t, "a-b", v[0].Interface())
+
+		_, err = jsoncall.CallFuncCurried(fn, `[""]`)
+		assert.EqualError(t, err, `separator required`)
+	})
+
+	t.Run("should support chains of curried functions", func(t *testing.T) {
+		fn := func(a int) func(int) func(int) int {
+			return func(b int) func(int) int {
+				return func(c int) int { return a + b + c }
+			}
+		}
+
+		c, err := jsoncall.CallFuncCurried(fn, `[1]`)
+		assert.NoError(t, err)
+
+		c, err = c.CallCurried(`[2]`)
+		assert.NoError(t, err)
+
+		v, err := c.Call(`[3]`)
+		assert.NoError(t, err)
+		assert.Equal(t, 6, v[0].Interface())
+		assert.Equal(t, "(number) -> number", jsoncall.Signature(c.Func().Type()))
+	})
+
+	t.Run("should error when the result is not a function", func(t *testing.T) {
+		_, err := jsoncall.CallFuncCurried(add, `[1, 2]`)
+		assert.Equal(t, jsoncall.ErrNotFunctionResult, err)
+
+		nilFunc := func() func() { return nil }
+		_, err = jsoncall.CallFuncCurried(nilFunc, `[]`)
+		assert.Equal(t, jsoncall.ErrNotFunctionResult, err)
+
+		pair := func() (func(), func()) { return func() {}, func() {} }
+		_, err = jsoncall.CallFuncCurried(pair, `[]`)
+		assert.Equal(t, jsoncall.ErrNotFunctionResult, err)
+	})
+
+	t.Run("should error when the returned function can't be called from JSON", func(t *testing.T) {
+		fn := func() func(a, b context.Context) { return func(a, b context.Context) {} }
+		_, err := jsoncall.CallFuncCurried(fn, `[]`)
+		assert.Equal(t, jsoncall.ErrMultipleContexts, err)
+	})
+
+	t.Run("should error on arguments of the outer function", func(t *testing.T) {
+		_, err := jsoncall.CallFuncCurried(greeter, `[5]`)
+		assert.EqualError(t, err, `Incorrect type number, expected object`)
+	})
+}
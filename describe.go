@@ -0,0 +1,81 @@
+package jsoncall
+
+import (
+	"reflect"
+	"sort"
+)
+
+// MethodInfo describes a function registered in a Registry, for example to
+// list an API from a discovery endpoint.
+type MethodInfo struct {
+	// Name is the registered name.
+	Name string `json:"name"`
+
+	// Params holds the JSON type names of the parameters, excluding any
+	// context, such as "number" or "...string" for variadic parameters.
+	Params []string `json:"params"`
+
+	// Results holds the JSON type names of the results, excluding errors.
+	Results []string `json:"results"`
+
+	// Context is true when the function accepts a context.
+	Context bool `json:"context"`
+
+	// Error is true when the last result is an error.
+	Error bool `json:"error"`
+
+	// Signature is the description returned by Signature.
+	Signature string `json:"signature"`
+}
+
+// Describe returns a description of each registered function, sorted by name
+// and then by arity, so overloads are listed separately. Aliases are not
+// listed.
+func (r *Registry) Describe() []MethodInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var methods []MethodInfo
+	for name, funcs := range r.funcs {
+		for _, f := range funcs {
+			methods = append(methods, describe(name, f.Type()))
+		}
+	}
+
+	sort.Slice(methods, func(i, j int) bool {
+		a, b := methods[i], methods[j]
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return len(a.Params) < len(b.Params)
+	})
+
+	return methods
+}
+
+// describe returns the description of a function registered under name.
+func describe(name string, t reflect.Type) MethodInfo {
+	m := MethodInfo{
+		Name:      name,
+		Params:    []string{},
+		Results:   []string{},
+		Error:     HasErrorResult(t),
+		Signature: Signature(t),
+	}
+
+	for i := 0; i < t.NumIn(); i++ {
+		if isContext(t.In(i)) {
+			m.Context = true
+			continue
+		}
+		m.Params = append(m.Params, paramName(t, i))
+	}
+
+	for i := 0; i < t.NumOut(); i++ {
+		if !isError(t.Out(i)) {
+			m.Results = append(m.Results, typeName(t.Out(i)))
+		}
+	}
+
+	return m
+}
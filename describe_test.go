@@ -0,0 +1,83 @@
+package jsoncall_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/tj/assert"
+	jsoncall "github.com/tj/go-jsoncall"
+)
+
+// Test describing registered functions.
+func TestRegistry_Describe(t *testing.T) {
+	t.Run("should describe each registered function", func(t *testing.T) {
+		r := jsoncall.NewRegistry()
+		assert.NoError(t, r.Register("math.add", add))
+		assert.NoError(t, r.Register("math.sum", sum))
+		assert.NoError(t, r.Register("users.add", addUserContext))
+		assert.NoError(t, r.Register("greet", func() string { return "Hello" }))
+		assert.NoError(t, r.Register("greet", func(ctx context.Context, name string) (string, error) { return name, nil }))
+		assert.NoError(t, r.Alias("add", "math.add"))
+
+		assert.Equal(t, []jsoncall.MethodInfo{
+			{
+				Name:      "greet",
+				Params:    []string{},
+				Results:   []string{"string"},
+				Signature: "() -> string",
+			},
+			{
+				Name:      "greet",
+				Params:    []string{"string"},
+				Results:   []string{"string"},
+				Context:   true,
+				Error:     true,
+				Signature: "(ctx, string) -> (string, error)",
+			},
+			{
+				Name:      "math.add",
+				Params:    []string{"number", "number"},
+				Results:   []string{"number"},
+				Signature: "(number, number) -> number",
+			},
+			{
+				Name:      "math.sum",
+				Params:    []string{"...number"},
+				Results:   []string{"number"},
+				Signature: "(...number) -> number",
+			},
+			{
+				Name:      "users.add",
+				Params:    []string{"object"},
+				Results:   []string{},
+				Context:   true,
+				Error:     true,
+				Signature: "(ctx, object) -> error",
+			},
+		}, r.Describe())
+	})
+
+	t.Run("should describe bound methods without their receiver", func(t *testing.T) {
+		r := jsoncall.NewRegistry()
+		assert.NoError(t, jsoncall.RegisterMethods(r, &petService{}, "pets."))
+
+		methods := r.Describe()
+		assert.Equal(t, "pets.Add", methods[0].Name)
+		assert.Equal(t, []string{"string"}, methods[0].Params)
+		assert.True(t, methods[0].Context)
+	})
+
+	t.Run("should marshal as JSON", func(t *testing.T) {
+		r := jsoncall.NewRegistry()
+		assert.NoError(t, r.Register("math.add", add))
+
+		b, err := json.Marshal(r.Describe())
+		assert.NoError(t, err)
+		assert.Equal(t, `[{"name":"math.add","params":["number","number"],"results":["number"],"context":false,"error":false,"signature":"(number, number) -\u003e number"}]`, string(b))
+	})
+
+	t.Run("should return nil for empty registries", func(t *testing.T) {
+		assert.Nil(t, jsoncall.NewRegistry().Describe())
+	})
+}
@@ -0,0 +1,104 @@
+package jsoncall
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// ErrDuplicateKey is returned when an object contains a repeated key.
+var ErrDuplicateKey error = newCallError(http.StatusBadRequest, "duplicate_key", "Duplicate object key")
+
+// DuplicateKeyError is returned when an object for a struct or map parameter
+// contains a repeated key. It unwraps to ErrDuplicateKey.
+type DuplicateKeyError struct {
+	Key string
+}
+
+// Error implementation.
+func (e *DuplicateKeyError) Error() string {
+	return fmt.Sprintf("Duplicate key %q", e.Key)
+}
+
+// Unwrap returns ErrDuplicateKey.
+func (e *DuplicateKeyError) Unwrap() error {
+	return ErrDuplicateKey
+}
+
+// WithRejectDuplicateKeys rejects objects containing repeated keys at any
+// depth for struct and map parameters, returning a *DuplicateKeyError wrapped
+// in an *ArgumentError, rather than silently taking the last value. Keys of
+// objects decoding into structs are compared case-insensitively, since
+// encoding/json would decode both into the same field, while those of maps
+// are compared exactly.
+func WithRejectDuplicateKeys() Option {
+	return func(v *config) {
+		v.rejectDuplicateKeys = true
+	}
+}
+
+// hasObjects returns true if the given type decodes from objects which are
+// checked for duplicate keys.
+func hasObjects(t reflect.Type) bool {
+	return isStructLike(t) || unrollPointer(t).Kind() == reflect.Map
+}
+
+// checkDuplicateKeys returns a *DuplicateKeyError for the first repeated key
+// of any object within raw, which decodes into t.
+func checkDuplicateKeys(raw json.RawMessage, t reflect.Type) error {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	return scanDuplicateKeys(dec, t)
+}
+
+// scanDuplicateKeys walks a JSON value decoding into t token by token.
+func scanDuplicateKeys(dec *json.Decoder, t reflect.Type) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	d, ok := tok.(json.Delim)
+	if !ok {
+		return nil
+	}
+
+	var seen map[string]bool
+	if d == '{' {
+		seen = make(map[string]bool)
+	}
+
+	fold := isStructObject(t)
+	for dec.More() {
+		vt := elemType(t)
+		if seen != nil {
+			tok, err := dec.Token()
+			if err != nil {
+				return err
+			}
+
+			key := tok.(string)
+			k := key
+			if fold {
+				k = strings.ToLower(k)
+			}
+
+			if seen[k] {
+				return &DuplicateKeyError{Key: key}
+			}
+			seen[k] = true
+			vt = valueType(t, key)
+		}
+
+		if err := scanDuplicateKeys(dec, vt); err != nil {
+			return err
+		}
+	}
+
+	// closing delimiter
+	_, err = dec.Token()
+	return err
+}
@@ -0,0 +1,79 @@
+package jsoncall_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/tj/assert"
+	jsoncall "github.com/tj/go-jsoncall"
+)
+
+// Test rejection of duplicate object keys.
+func TestWithRejectDuplicateKeys(t *testing.T) {
+	reject := jsoncall.WithRejectDuplicateKeys()
+
+	t.Run("should decode objects without duplicates", func(t *testing.T) {
+		args, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(addUser), `[{ "name": "Tobi", "email": "tobi@apex.sh" }]`, reject)
+		assert.NoError(t, err)
+		assert.Equal(t, User{Name: "Tobi", Email: "tobi@apex.sh"}, args[0].Interface())
+	})
+
+	t.Run("should reject duplicate struct keys", func(t *testing.T) {
+		_, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(addUser), `[{ "name": "Tobi", "name": "Loki" }]`, reject)
+		assert.EqualError(t, err, `Argument 0: Duplicate key "name"`)
+		assert.True(t, errors.Is(err, jsoncall.ErrDuplicateKey))
+		assert.Equal(t, "duplicate_key", jsoncall.ErrorCategory(err))
+
+		var e *jsoncall.DuplicateKeyError
+		assert.True(t, errors.As(err, &e))
+		assert.Equal(t, "name", e.Key)
+	})
+
+	t.Run("should compare struct keys case-insensitively", func(t *testing.T) {
+		_, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(addUser), `[{ "name": "Tobi", "Name": "Loki" }]`, reject)
+		assert.EqualError(t, err, `Argument 0: Duplicate key "Name"`)
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(addTags), `[{ "a": ["x"], "A": ["y"] }]`, reject)
+		assert.NoError(t, err)
+	})
+
+	t.Run("should reject duplicate keys of nested objects", func(t *testing.T) {
+		fn := func(id int, users []*User) {}
+		_, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[1, [{ "name": "Tobi" }, { "email": "a", "email": "b" }]]`, reject)
+		assert.EqualError(t, err, `Argument 1: Duplicate key "email"`)
+	})
+
+	t.Run("should reject duplicate map keys", func(t *testing.T) {
+		_, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(addTags), `[{ "a": ["x"], "a": ["y"] }]`, reject)
+		assert.EqualError(t, err, `Argument 0: Duplicate key "a"`)
+	})
+
+	t.Run("should compare keys of map fields exactly", func(t *testing.T) {
+		type Counts struct {
+			M map[string]int
+		}
+
+		fn := func(c Counts) {}
+		args, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[{ "M": { "a": 1, "A": 2 } }]`, reject)
+		assert.NoError(t, err)
+		assert.Equal(t, Counts{M: map[string]int{"a": 1, "A": 2}}, args[0].Interface())
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[{ "M": { "a": 1, "a": 2 } }]`, reject)
+		assert.EqualError(t, err, `Argument 0: Duplicate key "a"`)
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[{ "M": {}, "m": {} }]`, reject)
+		assert.EqualError(t, err, `Argument 0: Duplicate key "m"`)
+	})
+
+	t.Run("should reject duplicates before wrapping scalar map values", func(t *testing.T) {
+		_, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(addTags), `[{ "a": "x", "a": "y" }]`, reject, jsoncall.WithScalarMapValuesToSlice())
+		assert.EqualError(t, err, `Argument 0: Duplicate key "a"`)
+	})
+
+	t.Run("should take the last value by default", func(t *testing.T) {
+		args, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(addUser), `[{ "name": "Tobi", "name": "Loki" }]`)
+		assert.NoError(t, err)
+		assert.Equal(t, User{Name: "Loki"}, args[0].Interface())
+	})
+}
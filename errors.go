@@ -0,0 +1,262 @@
+package jsoncall
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// coder is implemented by errors suggesting an HTTP status code.
+type coder interface {
+	Code() int
+}
+
+// categorizer is implemented by errors with a category.
+type categorizer interface {
+	Category() string
+}
+
+// CallError is an error returned by the package, carrying a suggested HTTP
+// status code and a category distinguishing it from other errors.
+type CallError struct {
+	code     int
+	category string
+	message  string
+}
+
+// newCallError returns a new call error.
+func newCallError(code int, category, message string) *CallError {
+	return &CallError{
+		code:     code,
+		category: category,
+		message:  message,
+	}
+}
+
+// Error implementation.
+func (e *CallError) Error() string {
+	return e.message
+}
+
+// Code returns the suggested HTTP status code.
+func (e *CallError) Code() int {
+	return e.code
+}
+
+// Category returns the error category, such as "invalid_json".
+func (e *CallError) Category() string {
+	return e.category
+}
+
+// MarshalJSON implementation.
+func (e *CallError) MarshalJSON() ([]byte, error) {
+	return marshalError(e)
+}
+
+// ErrorCode returns the HTTP status code suggested by err, defaulting to
+// http.StatusInternalServerError for errors which don't suggest one, such as
+// those returned by the invoked function.
+func ErrorCode(err error) int {
+	var c coder
+	if errors.As(err, &c) {
+		return c.Code()
+	}
+	return http.StatusInternalServerError
+}
+
+// ErrorCategory returns the category of err, or an empty string for errors
+// which are not categorized, such as those returned by the invoked function.
+func ErrorCategory(err error) string {
+	var c categorizer
+	if errors.As(err, &c) {
+		return c.Category()
+	}
+	return ""
+}
+
+// ArityError is returned when the number of arguments passed does not match
+// the number of parameters. It unwraps to ErrTooFewArguments or
+// ErrTooManyArguments, so errors.Is may be used to distinguish the two.
+//
+// For overloaded registry functions Accepted holds every accepted count, and
+// Expected is the accepted count nearest to Got.
+type ArityError struct {
+	Expected int
+	Got      int
+	Accepted []int
+}
+
+// Error implementation.
+func (e *ArityError) Error() string {
+	expected := strconv.Itoa(e.Expected)
+	if len(e.Accepted) > 1 {
+		expected = joinCounts(e.Accepted)
+	}
+
+	if e.Got < e.Expected {
+		return fmt.Sprintf("Too few arguments: expected %s, got %d", expected, e.Got)
+	}
+	return fmt.Sprintf("Too many arguments: expected %s, got %d", expected, e.Got)
+}
+
+// Unwrap returns ErrTooFewArguments or ErrTooManyArguments.
+func (e *ArityError) Unwrap() error {
+	if e.Got < e.Expected {
+		return ErrTooFewArguments
+	}
+	return ErrTooManyArguments
+}
+
+// ArrayLengthError is returned when a JSON array does not match the length of
+// a fixed-size array parameter. It unwraps to ErrArrayLength.
+type ArrayLengthError struct {
+	Expected int
+	Got      int
+}
+
+// Error implementation.
+func (e *ArrayLengthError) Error() string {
+	return fmt.Sprintf("Incorrect array length: expected %d, got %d", e.Expected, e.Got)
+}
+
+// Unwrap returns ErrArrayLength.
+func (e *ArrayLengthError) Unwrap() error {
+	return ErrArrayLength
+}
+
+// ParameterTypeError is returned when a parameter has a kind which can't be
+// decoded from JSON, such as chan, func or unsafe.Pointer. It is wrapped in
+// an *ArgumentError holding the index, and unwraps to
+// ErrUnsupportedParameterType.
+type ParameterTypeError struct {
+	Kind reflect.Kind
+	Type reflect.Type
+}
+
+// Error implementation.
+func (e *ParameterTypeError) Error() string {
+	return fmt.Sprintf("Unsupported parameter type %s of kind %s", e.Type, e.Kind)
+}
+
+// Unwrap returns ErrUnsupportedParameterType.
+func (e *ParameterTypeError) Unwrap() error {
+	return ErrUnsupportedParameterType
+}
+
+// ArgumentTypeError is returned when a Go value is not assignable to its
+// parameter, Type being nil for nil values. It unwraps to ErrArgumentType.
+type ArgumentTypeError struct {
+	Type     reflect.Type
+	Expected reflect.Type
+}
+
+// Error implementation.
+func (e *ArgumentTypeError) Error() string {
+	got := "nil"
+	if e.Type != nil {
+		got = e.Type.String()
+	}
+	return fmt.Sprintf("Incorrect type %s, expected %s", got, e.Expected)
+}
+
+// Unwrap returns ErrArgumentType.
+func (e *ArgumentTypeError) Unwrap() error {
+	return ErrArgumentType
+}
+
+// RangeError is returned when an integer is out of range of an integer
+// parameter, or an integer nested within it, such as 300 or -1 for a uint8.
+// Field is the path of the nested value as reported by encoding/json, if any.
+// It unwraps to ErrOutOfRange.
+type RangeError struct {
+	Value string
+	Kind  reflect.Kind
+	Field string
+}
+
+// Error implementation.
+func (e *RangeError) Error() string {
+	if e.Field != "" {
+		return fmt.Sprintf("value %s out of range for %s at %q", e.Value, e.Kind, e.Field)
+	}
+	return fmt.Sprintf("value %s out of range for %s", e.Value, e.Kind)
+}
+
+// Unwrap returns ErrOutOfRange.
+func (e *RangeError) Unwrap() error {
+	return ErrOutOfRange
+}
+
+// IntegerError is returned when a number which is not whole is passed for an
+// integer parameter. It unwraps to ErrNotInteger.
+type IntegerError struct {
+	Value string
+}
+
+// Error implementation.
+func (e *IntegerError) Error() string {
+	return fmt.Sprintf("Expected an integer, got %s", e.Value)
+}
+
+// Unwrap returns ErrNotInteger.
+func (e *IntegerError) Unwrap() error {
+	return ErrNotInteger
+}
+
+// MarshalJSON implementation.
+func (e *ArityError) MarshalJSON() ([]byte, error) {
+	return marshalError(e)
+}
+
+// joinCounts returns a list of counts such as "1, 2 or 3".
+func joinCounts(counts []int) string {
+	var s []string
+	for _, n := range counts {
+		s = append(s, strconv.Itoa(n))
+	}
+	last := len(s) - 1
+	return strings.Join(s[:last], ", ") + " or " + s[last]
+}
+
+// errorJSON is the JSON representation of an error.
+type errorJSON struct {
+	Error errorDetail `json:"error"`
+}
+
+// errorDetail is the detail of an error's JSON representation.
+type errorDetail struct {
+	Message  string `json:"message"`
+	Type     string `json:"type,omitempty"`
+	Argument *int   `json:"argument,omitempty"`
+	Element  *int   `json:"element,omitempty"`
+}
+
+// marshalError returns the JSON representation of err, such as
+// {"error":{"message":"...","type":"incorrect_type","argument":1}}. The type
+// is the error's category, and the argument and element are the indices of
+// any *ArgumentError or *ElementError in the chain.
+func marshalError(err error) ([]byte, error) {
+	v := errorJSON{
+		Error: errorDetail{
+			Message: err.Error(),
+			Type:    ErrorCategory(err),
+		},
+	}
+
+	var a *ArgumentError
+	if errors.As(err, &a) {
+		v.Error.Argument = &a.Index
+	}
+
+	var e *ElementError
+	if errors.As(err, &e) {
+		v.Error.Argument = &e.Index
+		v.Error.Element = &e.Element
+	}
+
+	return json.Marshal(v)
+}
@@ -0,0 +1,110 @@
+package jsoncall_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/tj/assert"
+	jsoncall "github.com/tj/go-jsoncall"
+)
+
+// Test error codes and categories.
+func TestErrorCode(t *testing.T) {
+	t.Run("should categorize sentinel errors", func(t *testing.T) {
+		cases := []struct {
+			err      error
+			code     int
+			category string
+		}{
+			{jsoncall.ErrInvalidJSON, 400, "invalid_json"},
+			{jsoncall.ErrTooFewArguments, 400, "too_few_arguments"},
+			{jsoncall.ErrTooManyArguments, 400, "too_many_arguments"},
+			{jsoncall.ErrExpectedArray, 400, "expected_array"},
+			{jsoncall.ErrNotFunction, 500, "not_function"},
+		}
+
+		for _, c := range cases {
+			assert.Equal(t, c.code, jsoncall.ErrorCode(c.err), c.category)
+			assert.Equal(t, c.category, jsoncall.ErrorCategory(c.err))
+		}
+	})
+
+	t.Run("should categorize decoding errors", func(t *testing.T) {
+		_, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(add), `[1, "5"]`)
+		assert.Equal(t, 400, jsoncall.ErrorCode(err))
+		assert.Equal(t, "incorrect_type", jsoncall.ErrorCategory(err))
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(add), `[1, null]`, jsoncall.WithRejectNull())
+		assert.Equal(t, 400, jsoncall.ErrorCode(err))
+		assert.Equal(t, "null_not_allowed", jsoncall.ErrorCategory(err))
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(add), `[1, 2]`, jsoncall.WithValidator(func(int, reflect.Value) error {
+			return errors.New("invalid")
+		}))
+		assert.Equal(t, 400, jsoncall.ErrorCode(err))
+		assert.Equal(t, "invalid_argument", jsoncall.ErrorCategory(err))
+	})
+
+	t.Run("should categorize wrapped errors", func(t *testing.T) {
+		err := fmt.Errorf("calling: %w", jsoncall.ErrInvalidJSON)
+		assert.Equal(t, 400, jsoncall.ErrorCode(err))
+		assert.Equal(t, "invalid_json", jsoncall.ErrorCategory(err))
+	})
+
+	t.Run("should not categorize other errors", func(t *testing.T) {
+		_, err := jsoncall.CallFunc(addPet, `["Tobi"]`)
+		assert.Equal(t, 500, jsoncall.ErrorCode(err))
+		assert.Equal(t, "", jsoncall.ErrorCategory(err))
+	})
+}
+
+// Test JSON representations of errors.
+func TestErrorMarshalJSON(t *testing.T) {
+	t.Run("should marshal sentinel errors", func(t *testing.T) {
+		b, err := json.Marshal(jsoncall.ErrNotFunction)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{ "error": { "message": "Must pass a function", "type": "not_function" } }`, string(b))
+	})
+
+	t.Run("should marshal arity errors", func(t *testing.T) {
+		_, callErr := jsoncall.CallFunc(add, `[1]`)
+		b, err := json.Marshal(callErr)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{ "error": { "message": "Too few arguments: expected 2, got 1", "type": "too_few_arguments" } }`, string(b))
+	})
+
+	t.Run("should marshal unmarshal errors", func(t *testing.T) {
+		_, callErr := jsoncall.CallFunc(add, `[1, "2"]`)
+		b, err := json.Marshal(callErr)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{ "error": { "message": "Incorrect type string, expected number", "type": "incorrect_type", "argument": 1 } }`, string(b))
+
+		_, callErr = jsoncall.CallFunc(add, `[true, 2]`)
+		b, err = json.Marshal(callErr)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{ "error": { "message": "Incorrect type bool, expected number", "type": "incorrect_type", "argument": 0 } }`, string(b))
+
+		var e jsoncall.UnmarshalError
+		assert.True(t, errors.As(callErr, &e))
+		assert.Equal(t, "bool", (*json.UnmarshalTypeError)(&e).Value)
+
+		var a *jsoncall.ArgumentError
+		assert.True(t, errors.As(callErr, &a))
+		assert.Equal(t, 0, a.Index)
+	})
+
+	t.Run("should marshal argument errors with their index", func(t *testing.T) {
+		_, callErr := jsoncall.CallFunc(add, `[1, null]`, jsoncall.WithRejectNull())
+		b, err := json.Marshal(callErr)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{ "error": { "message": "Argument 1: null not allowed for non-pointer parameter", "type": "null_not_allowed", "argument": 1 } }`, string(b))
+	})
+
+	t.Run("should keep human messages via Error", func(t *testing.T) {
+		_, err := jsoncall.CallFunc(add, `[1]`)
+		assert.EqualError(t, err, `Too few arguments: expected 2, got 1`)
+	})
+}
@@ -0,0 +1,232 @@
+package jsoncall
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+)
+
+// Call0 invokes a function without params and returns its result. The
+// CallN and CallNContext helpers decode each argument directly into its
+// parameter type and invoke the function without reflection, which is
+// considerably faster than CallFunc for signatures known at compile time.
+func Call0[R any](fn func() R, args string, options ...Option) (r R, err error) {
+	c := newConfig(options)
+	if _, err = params(args, 0, c); err != nil {
+		return
+	}
+	return fn(), nil
+}
+
+// Call1 invokes a function with one argument derived from a json string.
+func Call1[A, R any](fn func(A) R, args string, options ...Option) (r R, err error) {
+	c := newConfig(options)
+	p, err := params(args, 1, c)
+	if err != nil {
+		return
+	}
+
+	a, err := param[A](p, 0, c)
+	if err != nil {
+		return
+	}
+
+	return fn(a), nil
+}
+
+// Call2 invokes a function with two arguments derived from a json string.
+func Call2[A, B, R any](fn func(A, B) R, args string, options ...Option) (r R, err error) {
+	c := newConfig(options)
+	p, err := params(args, 2, c)
+	if err != nil {
+		return
+	}
+
+	a, err := param[A](p, 0, c)
+	if err != nil {
+		return
+	}
+
+	b, err := param[B](p, 1, c)
+	if err != nil {
+		return
+	}
+
+	return fn(a, b), nil
+}
+
+// Call3 invokes a function with three arguments derived from a json string.
+func Call3[A, B, C, R any](fn func(A, B, C) R, args string, options ...Option) (r R, err error) {
+	c := newConfig(options)
+	p, err := params(args, 3, c)
+	if err != nil {
+		return
+	}
+
+	a, err := param[A](p, 0, c)
+	if err != nil {
+		return
+	}
+
+	b, err := param[B](p, 1, c)
+	if err != nil {
+		return
+	}
+
+	cc, err := param[C](p, 2, c)
+	if err != nil {
+		return
+	}
+
+	return fn(a, b, cc), nil
+}
+
+// Call0Context invokes a function taking only a context and returns its result.
+func Call0Context[R any](fn func(context.Context) R, args string, options ...Option) (r R, err error) {
+	c := newConfig(options)
+	if _, err = params(args, 0, c); err != nil {
+		return
+	}
+	ctx, err := checkedContext(c)
+	if err != nil {
+		return
+	}
+
+	return fn(ctx), nil
+}
+
+// Call1Context invokes a function taking a context and one argument derived from a json string.
+func Call1Context[A, R any](fn func(context.Context, A) R, args string, options ...Option) (r R, err error) {
+	c := newConfig(options)
+	p, err := params(args, 1, c)
+	if err != nil {
+		return
+	}
+
+	a, err := param[A](p, 0, c)
+	if err != nil {
+		return
+	}
+
+	ctx, err := checkedContext(c)
+	if err != nil {
+		return
+	}
+
+	return fn(ctx, a), nil
+}
+
+// Call2Context invokes a function taking a context and two arguments derived from a json string.
+func Call2Context[A, B, R any](fn func(context.Context, A, B) R, args string, options ...Option) (r R, err error) {
+	c := newConfig(options)
+	p, err := params(args, 2, c)
+	if err != nil {
+		return
+	}
+
+	a, err := param[A](p, 0, c)
+	if err != nil {
+		return
+	}
+
+	b, err := param[B](p, 1, c)
+	if err != nil {
+		return
+	}
+
+	ctx, err := checkedContext(c)
+	if err != nil {
+		return
+	}
+
+	return fn(ctx, a, b), nil
+}
+
+// Call3Context invokes a function taking a context and three arguments derived from a json string.
+func Call3Context[A, B, C, R any](fn func(context.Context, A, B, C) R, args string, options ...Option) (r R, err error) {
+	c := newConfig(options)
+	p, err := params(args, 3, c)
+	if err != nil {
+		return
+	}
+
+	a, err := param[A](p, 0, c)
+	if err != nil {
+		return
+	}
+
+	b, err := param[B](p, 1, c)
+	if err != nil {
+		return
+	}
+
+	cc, err := param[C](p, 2, c)
+	if err != nil {
+		return
+	}
+
+	ctx, err := checkedContext(c)
+	if err != nil {
+		return
+	}
+
+	return fn(ctx, a, b, cc), nil
+}
+
+// params parses exactly n params from a json string, or the codec's encoding.
+func params(s string, n int, c *config) ([]json.RawMessage, error) {
+	if c.hasCodec() {
+		p, err := c.splitParams(s)
+		if err != nil {
+			return nil, err
+		}
+		return c.checkArity(p, n, n)
+	}
+
+	s, err := c.convert(s)
+	if err != nil {
+		return nil, err
+	}
+
+	s, _, err = c.extractParams(blankAsEmpty(s))
+	if err != nil {
+		return nil, err
+	}
+
+	s, _, err = c.objectArgument(s, n)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.checkDepth(s); err != nil {
+		return nil, err
+	}
+
+	if n == 0 && isEmptyArray(s) {
+		return nil, nil
+	}
+
+	p, err := parseParams(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.checkArity(p, n, n)
+}
+
+// param decodes the param at index i.
+func param[T any](p []json.RawMessage, i int, c *config) (v T, err error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	err = c.paramConfig(i).decodeArgument(i, t, p[i], &v)
+	return
+}
+
+// checkedContext returns a new context, or its error when WithCheckContext is
+// used and the context is already done.
+func checkedContext(c *config) (context.Context, error) {
+	ctx := c.context()
+	if c.checkContext && ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	return ctx, nil
+}
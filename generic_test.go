@@ -0,0 +1,105 @@
+package jsoncall_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tj/assert"
+	jsoncall "github.com/tj/go-jsoncall"
+)
+
+// Test calling of functions without reflection.
+func TestCallN(t *testing.T) {
+	t.Run("should support no params", func(t *testing.T) {
+		v, err := jsoncall.Call0(func() string { return "hello" }, `[]`)
+		assert.NoError(t, err)
+		assert.Equal(t, "hello", v)
+
+		_, err = jsoncall.Call0(func() string { return "hello" }, `[1]`)
+		assert.EqualError(t, err, `Too many arguments: expected 0, got 1`)
+	})
+
+	t.Run("should support one param", func(t *testing.T) {
+		v, err := jsoncall.Call1(abs, `[-5.5]`)
+		assert.NoError(t, err)
+		assert.Equal(t, 5.5, v)
+
+		u, err := jsoncall.Call1(func(u User) string { return u.Name }, `[{ "name": "Tobi" }]`)
+		assert.NoError(t, err)
+		assert.Equal(t, "Tobi", u)
+	})
+
+	t.Run("should support two params", func(t *testing.T) {
+		v, err := jsoncall.Call2(add, `[1, 2]`)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, v)
+	})
+
+	t.Run("should support three params", func(t *testing.T) {
+		join := func(a, b, c string) string { return a + b + c }
+		v, err := jsoncall.Call3(join, `["a", "b", "c"]`)
+		assert.NoError(t, err)
+		assert.Equal(t, "abc", v)
+	})
+
+	t.Run("should support context", func(t *testing.T) {
+		type key struct{}
+		ctx := context.WithValue(context.Background(), key{}, "value")
+
+		v, err := jsoncall.Call0Context(func(ctx context.Context) interface{} { return ctx.Value(key{}) }, ``, jsoncall.WithContext(ctx))
+		assert.NoError(t, err)
+		assert.Equal(t, "value", v)
+
+		n, err := jsoncall.Call1Context(func(ctx context.Context, nums []int) int { return sum(nums...) }, `[[1, 2, 3]]`)
+		assert.NoError(t, err)
+		assert.Equal(t, 6, n)
+
+		n, err = jsoncall.Call2Context(func(ctx context.Context, a, b int) int { return a * b }, `[2, 3]`)
+		assert.NoError(t, err)
+		assert.Equal(t, 6, n)
+
+		n, err = jsoncall.Call3Context(func(ctx context.Context, a, b, c int) int { return a + b + c }, `[1, 2, 3]`)
+		assert.NoError(t, err)
+		assert.Equal(t, 6, n)
+	})
+
+	t.Run("should skip cancelled contexts via WithCheckContext", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := jsoncall.Call1Context(func(ctx context.Context, a int) int { return a }, `[1]`, jsoncall.WithContext(ctx), jsoncall.WithCheckContext())
+		assert.Equal(t, context.Canceled, err)
+	})
+
+	t.Run("should error like CallFunc", func(t *testing.T) {
+		_, err := jsoncall.Call2(add, `[1]`)
+		assert.EqualError(t, err, `Too few arguments: expected 2, got 1`)
+
+		_, err = jsoncall.Call2(add, `[1, "5"]`)
+		assert.EqualError(t, err, `Incorrect type string, expected number`)
+
+		_, err = jsoncall.Call2(add, `[1, hey]`)
+		assert.EqualError(t, err, `Invalid JSON`)
+
+		_, err = jsoncall.Call2(add, `[1, null]`, jsoncall.WithRejectNull())
+		assert.EqualError(t, err, `Argument 1: null not allowed for non-pointer parameter`)
+	})
+}
+
+// Benchmark calling without reflection.
+func BenchmarkCall2(b *testing.B) {
+	b.SetBytes(1)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		jsoncall.Call2(add, `[1, 2]`)
+	}
+}
+
+// Benchmark calling with reflection, for comparison with BenchmarkCall2.
+func BenchmarkCallFunc2(b *testing.B) {
+	b.SetBytes(1)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		jsoncall.CallFunc(add, `[1, 2]`)
+	}
+}
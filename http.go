@@ -0,0 +1,178 @@
+package jsoncall
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// ErrMethodNotAllowed is returned by Handler for requests other than POST.
+var ErrMethodNotAllowed error = newCallError(http.StatusMethodNotAllowed, "method_not_allowed", "Method not allowed")
+
+// ErrBodyTooLarge is returned by Handler for request bodies larger than WithMaxBodySize allows.
+var ErrBodyTooLarge error = newCallError(http.StatusRequestEntityTooLarge, "body_too_large", "Request body too large")
+
+// ErrUnsupportedMediaType is returned by Handler for request bodies whose Content-Type has no codec.
+var ErrUnsupportedMediaType error = newCallError(http.StatusUnsupportedMediaType, "unsupported_media_type", "Unsupported media type")
+
+// defaultMaxBodySize is the default limit of request bodies read by Handler.
+const defaultMaxBodySize = 1 << 20
+
+// WithMaxBodySize limits the request bodies read by Handler to n bytes,
+// rejecting larger bodies with ErrBodyTooLarge, 1MB by default. A negative
+// n removes the limit.
+func WithMaxBodySize(n int64) Option {
+	return func(v *config) {
+		v.maxBodySize = n
+	}
+}
+
+// WithContentCodec decodes the arguments of Handler requests whose
+// Content-Type is the given media type, such as "application/msgpack", with
+// codec. Requests without a Content-Type, or of "application/json", are
+// decoded as JSON unless a codec is given for it.
+func WithContentCodec(mediaType string, codec Codec) Option {
+	return func(v *config) {
+		if v.contentCodecs == nil {
+			v.contentCodecs = make(map[string]Codec)
+		}
+		v.contentCodecs[strings.ToLower(mediaType)] = codec
+	}
+}
+
+// envelope is a request naming the function to call and its arguments.
+type envelope struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+// Handler returns an http.Handler which dispatches POST requests to functions
+// of the registry. The function name is taken from the URL path, for example
+// POST /math.add with a body of [1, 2], or when the path is "/" from a JSON
+// envelope such as {"method": "math.add", "params": [1, 2]}.
+//
+// Results are written as JSON using MarshalResults, and errors as
+// {"error": {"message": "...", "type": "..."}} with the status code given by
+// ErrorCode. The request's context is passed to functions which expect
+// one, along with the span context of any traceparent header, the options
+// given are applied after it. Bodies are limited in size by WithMaxBodySize.
+//
+// The Content-Type of the request selects the codec of its arguments, given
+// by WithContentCodec, with ErrUnsupportedMediaType returned for others.
+// Envelopes are only read from JSON bodies, other codecs requiring the
+// function to be named by the path.
+func Handler(reg *Registry, options ...Option) http.Handler {
+	c := newConfig(options)
+
+	limit := c.maxBodySize
+	if limit == 0 {
+		limit = defaultMaxBodySize
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeError(w, ErrMethodNotAllowed)
+			return
+		}
+
+		codec, err := c.requestCodec(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		if limit > 0 {
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, bodyError(err))
+			return
+		}
+
+		name := strings.Trim(r.URL.Path, "/")
+		args := string(body)
+
+		if name == "" && codec == JSONCodec {
+			var e envelope
+			if err := json.Unmarshal(body, &e); err != nil {
+				writeError(w, ErrInvalidJSON)
+				return
+			}
+			name = e.Method
+			args = string(e.Params)
+		}
+
+		if strings.TrimSpace(args) == "" && codec == JSONCodec {
+			args = "[]"
+		}
+
+		opts := []Option{WithContext(r.Context())}
+		if tp := r.Header.Get("traceparent"); tp != "" {
+			opts = append(opts, WithTraceparent(tp))
+		}
+		opts = append(opts, options...)
+		opts = append(opts, WithCodec(codec))
+
+		values, err := reg.Call(name, args, opts...)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		b, err := MarshalResults(values, options...)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(b)
+	})
+}
+
+// requestCodec returns the codec for the request's Content-Type, JSONCodec
+// being the default.
+func (c *config) requestCodec(r *http.Request) (Codec, error) {
+	ct := r.Header.Get("Content-Type")
+	if ct == "" {
+		ct = "application/json"
+	}
+
+	mediaType, _, err := mime.ParseMediaType(ct)
+	if err != nil {
+		return nil, ErrUnsupportedMediaType
+	}
+
+	if codec, ok := c.contentCodecs[mediaType]; ok {
+		return codec, nil
+	}
+
+	if mediaType == "application/json" {
+		return JSONCodec, nil
+	}
+
+	return nil, ErrUnsupportedMediaType
+}
+
+// bodyError returns the error for a failure reading the request body.
+func bodyError(err error) error {
+	var e *http.MaxBytesError
+	if errors.As(err, &e) {
+		return ErrBodyTooLarge
+	}
+	return newCallError(http.StatusBadRequest, "invalid_body", "Error reading request body")
+}
+
+// writeError writes an error response.
+func writeError(w http.ResponseWriter, err error) {
+	b, _ := marshalError(err)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(ErrorCode(err))
+	w.Write(b)
+}
@@ -0,0 +1,171 @@
+package jsoncall_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tj/assert"
+	jsoncall "github.com/tj/go-jsoncall"
+)
+
+type requestIDKey struct{}
+
+// Test the HTTP handler adapter.
+func TestHandler(t *testing.T) {
+	r := jsoncall.NewRegistry()
+	assert.NoError(t, r.Register("math.add", add))
+	assert.NoError(t, r.Register("pets.add", addPet))
+	assert.NoError(t, r.Register("request.id", func(ctx context.Context) string {
+		return ctx.Value(requestIDKey{}).(string)
+	}))
+
+	h := jsoncall.Handler(r)
+
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req = req.WithContext(context.WithValue(req.Context(), requestIDKey{}, "abc"))
+		res := httptest.NewRecorder()
+		h.ServeHTTP(res, req)
+		return res
+	}
+
+	t.Run("should call functions named by the path", func(t *testing.T) {
+		res := serve("POST", "/math.add", `[1, 2]`)
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, "application/json", res.Header().Get("Content-Type"))
+		assert.Equal(t, `3`, res.Body.String())
+	})
+
+	t.Run("should call functions named by an envelope", func(t *testing.T) {
+		res := serve("POST", "/", `{ "method": "math.add", "params": [1, 2] }`)
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, `3`, res.Body.String())
+	})
+
+	t.Run("should pass the request context", func(t *testing.T) {
+		res := serve("POST", "/request.id", ``)
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, `"abc"`, res.Body.String())
+
+		res = serve("POST", "/", `{ "method": "request.id" }`)
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, `"abc"`, res.Body.String())
+	})
+
+	t.Run("should write errors with their status codes", func(t *testing.T) {
+		res := serve("POST", "/math.add", `[1]`)
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Equal(t, "application/json", res.Header().Get("Content-Type"))
+		assert.JSONEq(t, `{ "error": { "message": "Too few arguments: expected 2, got 1", "type": "too_few_arguments" } }`, res.Body.String())
+
+		res = serve("POST", "/math.add", `[1, "2"]`)
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.JSONEq(t, `{ "error": { "message": "Incorrect type string, expected number", "type": "incorrect_type", "argument": 1 } }`, res.Body.String())
+
+		res = serve("POST", "/math.sub", `[1, 2]`)
+		assert.Equal(t, http.StatusNotFound, res.Code)
+
+		res = serve("POST", "/pets.add", `["Tobi"]`)
+		assert.Equal(t, http.StatusInternalServerError, res.Code)
+		assert.JSONEq(t, `{ "error": { "message": "error adding pet" } }`, res.Body.String())
+
+		res = serve("POST", "/", `{`)
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.JSONEq(t, `{ "error": { "message": "Invalid JSON", "type": "invalid_json" } }`, res.Body.String())
+	})
+
+	t.Run("should pass the traceparent header", func(t *testing.T) {
+		r := jsoncall.NewRegistry()
+		assert.NoError(t, r.Register("trace.id", func(ctx context.Context) string {
+			s, _ := jsoncall.SpanContextFromContext(ctx)
+			return s.TraceID
+		}))
+
+		h := jsoncall.Handler(r)
+
+		req := httptest.NewRequest("POST", "/trace.id", strings.NewReader(`[]`))
+		req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		res := httptest.NewRecorder()
+		h.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, `"4bf92f3577b34da6a3ce929d0e0e4736"`, res.Body.String())
+
+		req = httptest.NewRequest("POST", "/trace.id", strings.NewReader(`[]`))
+		res = httptest.NewRecorder()
+		h.ServeHTTP(res, req)
+		assert.Equal(t, `""`, res.Body.String())
+	})
+
+	t.Run("should limit the size of bodies", func(t *testing.T) {
+		h := jsoncall.Handler(r, jsoncall.WithMaxBodySize(8))
+
+		req := httptest.NewRequest("POST", "/math.add", strings.NewReader(`[1, 2]`))
+		res := httptest.NewRecorder()
+		h.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusOK, res.Code)
+
+		req = httptest.NewRequest("POST", "/math.add", strings.NewReader(`[1, 2, 3, 4]`))
+		res = httptest.NewRecorder()
+		h.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, res.Code)
+		assert.JSONEq(t, `{ "error": { "message": "Request body too large", "type": "body_too_large" } }`, res.Body.String())
+
+		res = serve("POST", "/math.add", "["+strings.Repeat(" ", 1<<20)+"1, 2]")
+		assert.Equal(t, http.StatusRequestEntityTooLarge, res.Code)
+
+		h = jsoncall.Handler(r, jsoncall.WithMaxBodySize(-1))
+		req = httptest.NewRequest("POST", "/math.add", strings.NewReader("["+strings.Repeat(" ", 1<<20)+"1, 2]"))
+		res = httptest.NewRecorder()
+		h.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusOK, res.Code)
+	})
+
+	t.Run("should decode arguments with the codec of the content type", func(t *testing.T) {
+		h := jsoncall.Handler(r, jsoncall.WithContentCodec("text/csv", csvCodec{}))
+
+		post := func(contentType, path, body string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest("POST", path, strings.NewReader(body))
+			if contentType != "" {
+				req.Header.Set("Content-Type", contentType)
+			}
+			res := httptest.NewRecorder()
+			h.ServeHTTP(res, req)
+			return res
+		}
+
+		res := post("text/csv", "/math.add", "1,2")
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, "application/json", res.Header().Get("Content-Type"))
+		assert.Equal(t, `3`, res.Body.String())
+
+		res = post("Text/CSV; charset=utf-8", "/math.add", "1,2")
+		assert.Equal(t, http.StatusOK, res.Code)
+
+		res = post("text/csv", "/math.add", "1,two")
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+
+		res = post("application/json", "/math.add", `[1, 2]`)
+		assert.Equal(t, `3`, res.Body.String())
+
+		res = post("application/json; charset=utf-8", "/", `{ "method": "math.add", "params": [1, 2] }`)
+		assert.Equal(t, `3`, res.Body.String())
+
+		res = post("", "/math.add", `[1, 2]`)
+		assert.Equal(t, `3`, res.Body.String())
+
+		res = post("application/msgpack", "/math.add", "1,2")
+		assert.Equal(t, http.StatusUnsupportedMediaType, res.Code)
+		assert.JSONEq(t, `{ "error": { "message": "Unsupported media type", "type": "unsupported_media_type" } }`, res.Body.String())
+
+		res = post("text/csv;;", "/math.add", "1,2")
+		assert.Equal(t, http.StatusUnsupportedMediaType, res.Code)
+	})
+
+	t.Run("should reject methods other than POST", func(t *testing.T) {
+		res := serve("GET", "/math.add", ``)
+		assert.Equal(t, http.StatusMethodNotAllowed, res.Code)
+	})
+}
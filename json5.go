@@ -0,0 +1,362 @@
+//go:build json5
+
+package jsoncall
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// ErrInvalidJSON5 is returned when JSON5 input is malformed or has no JSON equivalent.
+var ErrInvalidJSON5 error = newCallError(http.StatusBadRequest, "invalid_json5", "Invalid JSON5")
+
+// WithJSON5 accepts arguments in JSON5 syntax, such as ['Tobi', {age: 3,}],
+// which are converted to JSON before being processed as usual. Comments,
+// unquoted keys, single quoted strings, trailing commas and hexadecimal
+// numbers are supported, while Infinity and NaN return ErrInvalidJSON5.
+// Spans returned by ArgumentsOfFuncMeta are relative to the converted JSON.
+//
+// This option is only available with the json5 build tag.
+func WithJSON5() Option {
+	return func(v *config) {
+		v.syntax = json5ToJSON
+	}
+}
+
+// json5Parser converts JSON5 to JSON.
+type json5Parser struct {
+	s   string
+	i   int
+	buf bytes.Buffer
+}
+
+// json5ToJSON converts a JSON5 document to JSON.
+func json5ToJSON(s string) (string, error) {
+	p := &json5Parser{s: s}
+
+	p.space()
+	if p.i == len(p.s) {
+		return "", nil
+	}
+
+	if err := p.value(); err != nil {
+		return "", err
+	}
+
+	p.space()
+	if p.i < len(p.s) {
+		return "", ErrInvalidJSON5
+	}
+
+	return p.buf.String(), nil
+}
+
+// space skips whitespace and comments.
+func (p *json5Parser) space() {
+	for p.i < len(p.s) {
+		switch {
+		case strings.HasPrefix(p.s[p.i:], "//"):
+			if n := strings.IndexAny(p.s[p.i:], "\n\r\u2028\u2029"); n >= 0 {
+				p.i += n
+			} else {
+				p.i = len(p.s)
+			}
+		case strings.HasPrefix(p.s[p.i:], "/*"):
+			if n := strings.Index(p.s[p.i+2:], "*/"); n >= 0 {
+				p.i += n + 4
+			} else {
+				// left for value to reject
+				return
+			}
+		default:
+			r, size := utf8.DecodeRuneInString(p.s[p.i:])
+			if !isJSON5Space(r) {
+				return
+			}
+			p.i += size
+		}
+	}
+}
+
+// isJSON5Space returns true if r is JSON5 whitespace.
+func isJSON5Space(r rune) bool {
+	switch r {
+	case '\t', '\n', '\r', '\v', '\f', '\u2028', '\u2029', '\ufeff':
+		return true
+	default:
+		return unicode.Is(unicode.Zs, r)
+	}
+}
+
+// value converts a value.
+func (p *json5Parser) value() error {
+	p.space()
+	if p.i >= len(p.s) {
+		return ErrInvalidJSON5
+	}
+
+	switch c := p.s[p.i]; {
+	case c == '{':
+		return p.collection('}', true)
+	case c == '[':
+		return p.collection(']', false)
+	case c == '"' || c == '\'':
+		s, err := p.string()
+		if err != nil {
+			return err
+		}
+		b, _ := json.Marshal(s)
+		p.buf.Write(b)
+		return nil
+	case c == '-' || c == '+' || c == '.' || (c >= '0' && c <= '9'):
+		return p.number()
+	default:
+		switch p.identifier() {
+		case "true":
+			p.buf.WriteString("true")
+		case "false":
+			p.buf.WriteString("false")
+		case "null":
+			p.buf.WriteString("null")
+		default:
+			return ErrInvalidJSON5
+		}
+		return nil
+	}
+}
+
+// collection converts an array, or an object when keyed, allowing a trailing comma.
+func (p *json5Parser) collection(close byte, keyed bool) error {
+	p.buf.WriteByte(p.s[p.i])
+	p.i++
+
+	for n := 0; ; n++ {
+		p.space()
+		if p.i >= len(p.s) {
+			return ErrInvalidJSON5
+		}
+
+		if p.s[p.i] == close {
+			p.i++
+			break
+		}
+
+		if n > 0 {
+			p.buf.WriteByte(',')
+		}
+
+		if keyed {
+			if err := p.key(); err != nil {
+				return err
+			}
+		}
+
+		if err := p.value(); err != nil {
+			return err
+		}
+
+		p.space()
+		if p.i < len(p.s) && p.s[p.i] == ',' {
+			p.i++
+		} else if p.i >= len(p.s) || p.s[p.i] != close {
+			return ErrInvalidJSON5
+		}
+	}
+
+	p.buf.WriteByte(close)
+	return nil
+}
+
+// key converts an object key, which may be quoted or an identifier, and its colon.
+func (p *json5Parser) key() error {
+	var key string
+	if c := p.s[p.i]; c == '"' || c == '\'' {
+		s, err := p.string()
+		if err != nil {
+			return err
+		}
+		key = s
+	} else {
+		key = p.identifier()
+		if key == "" {
+			return ErrInvalidJSON5
+		}
+	}
+
+	p.space()
+	if p.i >= len(p.s) || p.s[p.i] != ':' {
+		return ErrInvalidJSON5
+	}
+	p.i++
+
+	b, _ := json.Marshal(key)
+	p.buf.Write(b)
+	p.buf.WriteByte(':')
+	return nil
+}
+
+// identifier returns the identifier at the current position, which may be empty.
+func (p *json5Parser) identifier() string {
+	start := p.i
+	for p.i < len(p.s) {
+		r, size := utf8.DecodeRuneInString(p.s[p.i:])
+		if !(r == '_' || r == '$' || unicode.IsLetter(r) || (p.i > start && unicode.IsDigit(r))) {
+			break
+		}
+		p.i += size
+	}
+	return p.s[start:p.i]
+}
+
+// number converts a number, which may be hexadecimal or have a leading plus
+// sign, or leading or trailing decimal point.
+func (p *json5Parser) number() error {
+	start := p.i
+	for p.i < len(p.s) && strings.IndexByte("+-.0123456789abcdefABCDEFxX", p.s[p.i]) >= 0 {
+		p.i++
+	}
+
+	text := p.s[start:p.i]
+
+	sign := ""
+	switch {
+	case strings.HasPrefix(text, "+"):
+		text = text[1:]
+	case strings.HasPrefix(text, "-"):
+		sign, text = "-", text[1:]
+	}
+
+	if strings.HasPrefix(text, "0x") || strings.HasPrefix(text, "0X") {
+		n, err := strconv.ParseUint(text[2:], 16, 64)
+		if err != nil {
+			return ErrInvalidJSON5
+		}
+		p.buf.WriteString(sign + strconv.FormatUint(n, 10))
+		return nil
+	}
+
+	if strings.HasPrefix(text, ".") {
+		text = "0" + text
+	}
+
+	if i := strings.IndexByte(text, '.'); i >= 0 && (i+1 == len(text) || text[i+1] < '0' || text[i+1] > '9') {
+		text = text[:i+1] + "0" + text[i+1:]
+	}
+
+	if !json.Valid([]byte(text)) || text[0] == '-' {
+		return ErrInvalidJSON5
+	}
+
+	p.buf.WriteString(sign + text)
+	return nil
+}
+
+// string returns the contents of a single or double quoted string.
+func (p *json5Parser) string() (string, error) {
+	quote := p.s[p.i]
+	p.i++
+
+	var b strings.Builder
+	for p.i < len(p.s) {
+		c := p.s[p.i]
+
+		switch {
+		case c == quote:
+			p.i++
+			return b.String(), nil
+		case c == '\n' || c == '\r':
+			return "", ErrInvalidJSON5
+		case c == '\\':
+			p.i++
+			if err := p.escape(&b); err != nil {
+				return "", err
+			}
+		default:
+			b.WriteByte(c)
+			p.i++
+		}
+	}
+
+	return "", ErrInvalidJSON5
+}
+
+// escape writes the escape sequence following a backslash.
+func (p *json5Parser) escape(b *strings.Builder) error {
+	if p.i >= len(p.s) {
+		return ErrInvalidJSON5
+	}
+
+	c := p.s[p.i]
+	p.i++
+
+	switch c {
+	case 'b':
+		b.WriteByte('\b')
+	case 'f':
+		b.WriteByte('\f')
+	case 'n':
+		b.WriteByte('\n')
+	case 'r':
+		// line continuation
+		if p.i < len(p.s) && p.s[p.i] == '\n' {
+			p.i++
+			return nil
+		}
+		b.WriteByte('\r')
+	case 't':
+		b.WriteByte('\t')
+	case 'v':
+		b.WriteByte('\v')
+	case '0':
+		b.WriteByte(0)
+	case '\n':
+		// line continuation
+	case 'x':
+		n, err := p.hex(2)
+		if err != nil {
+			return err
+		}
+		b.WriteRune(rune(n))
+	case 'u':
+		n, err := p.hex(4)
+		if err != nil {
+			return err
+		}
+
+		r := rune(n)
+		if utf16.IsSurrogate(r) && strings.HasPrefix(p.s[p.i:], "\\u") {
+			p.i += 2
+			n, err := p.hex(4)
+			if err != nil {
+				return err
+			}
+			r = utf16.DecodeRune(r, rune(n))
+		}
+		b.WriteRune(r)
+	default:
+		b.WriteByte(c)
+	}
+
+	return nil
+}
+
+// hex returns the value of n hexadecimal digits.
+func (p *json5Parser) hex(n int) (uint64, error) {
+	if p.i+n > len(p.s) {
+		return 0, ErrInvalidJSON5
+	}
+
+	v, err := strconv.ParseUint(p.s[p.i:p.i+n], 16, 32)
+	if err != nil {
+		return 0, ErrInvalidJSON5
+	}
+
+	p.i += n
+	return v, nil
+}
@@ -0,0 +1,109 @@
+//go:build json5
+
+package jsoncall_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/tj/assert"
+	jsoncall "github.com/tj/go-jsoncall"
+)
+
+// Test argument decoding from JSON5.
+func TestWithJSON5(t *testing.T) {
+	json5 := jsoncall.WithJSON5()
+
+	t.Run("should decode relaxed syntax", func(t *testing.T) {
+		args := `[
+			// the user
+			{ name: 'Tobi', "email": 'tobi@apex.sh', },
+		]`
+
+		vals, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(addUser), args, json5)
+		assert.NoError(t, err)
+		assert.Equal(t, User{Name: "Tobi", Email: "tobi@apex.sh"}, vals[0].Interface())
+	})
+
+	t.Run("should convert values", func(t *testing.T) {
+		fn := func(v interface{}) {}
+
+		cases := []struct {
+			json5  string
+			output interface{}
+		}{
+			{`[0x1F]`, 31.0},
+			{`[-0xff]`, -255.0},
+			{`[+5]`, 5.0},
+			{`[.5]`, 0.5},
+			{`[5.]`, 5.0},
+			{`[1e3]`, 1000.0},
+			{`[null]`, nil},
+			{`[true /* yes */]`, true},
+			{`['it\'s']`, "it's"},
+			{`['say "hi"']`, `say "hi"`},
+			{`['\x41é\t']`, "Aé\t"},
+			{`['😀']`, "\U0001F600"},
+			{"['line \\\n continued']", "line  continued"},
+			{`[{ $id: 1, _b2: [] }]`, map[string]interface{}{"$id": 1.0, "_b2": []interface{}{}}},
+		}
+
+		for _, c := range cases {
+			t.Run(c.json5, func(t *testing.T) {
+				vals, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), c.json5, json5)
+				assert.NoError(t, err)
+				assert.Equal(t, c.output, vals[0].Interface())
+			})
+		}
+	})
+
+	t.Run("should process arguments as usual", func(t *testing.T) {
+		v, err := jsoncall.CallFunc(add, `[1, 2,]`, json5)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, v[0].Interface())
+
+		_, err = jsoncall.CallFunc(add, `[1, '2']`, json5)
+		assert.EqualError(t, err, `Incorrect type string, expected number`)
+
+		_, err = jsoncall.CallFunc(add, `[1]`, json5)
+		assert.EqualError(t, err, `Too few arguments: expected 2, got 1`)
+
+		_, err = jsoncall.CallFunc(func() {}, ` // nothing`, json5)
+		assert.NoError(t, err)
+
+		n, err := jsoncall.Call2(add, `[1, 2,]`, json5)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, n)
+	})
+
+	t.Run("should resolve registry overloads after conversion", func(t *testing.T) {
+		r := jsoncall.NewRegistry()
+		assert.NoError(t, r.Register("add", add))
+		assert.NoError(t, r.Register("add", func(a, b, c int) int { return a + b + c }))
+
+		v, err := r.Call("add", `[1, 2, 3,]`, json5)
+		assert.NoError(t, err)
+		assert.Equal(t, 6, v[0].Interface())
+	})
+
+	t.Run("should error on invalid JSON5", func(t *testing.T) {
+		cases := []string{
+			`[1, 2`,
+			`[Infinity]`,
+			`[NaN]`,
+			`[01]`,
+			`['unterminated]`,
+			"['new\nline']",
+			`[1,,2]`,
+			`[{ a b: 1 }]`,
+			`[1] 2`,
+			`[1 /* open`,
+			`[0xZZ]`,
+		}
+
+		for _, args := range cases {
+			_, err := jsoncall.CallFunc(add, args, json5)
+			assert.Equal(t, jsoncall.ErrInvalidJSON5, err, args)
+		}
+	})
+}
@@ -1,21 +1,75 @@
 // Package jsoncall provides utilities for invoking Go functions from JSON.
+//
+// Generic functions must be instantiated before use, for example
+// CallFunc(Max[int], `[1, 2]`). An instantiated generic function, method
+// value or method of a generic type reflects as a regular function or method,
+// so no special handling is required.
 package jsoncall
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"net/url"
 	"reflect"
 	"strings"
+	"time"
 )
 
 // config settings.
 type config struct {
-	contextFunc  ContextFunc
-	arity        int
-	offset       int
-	contextIndex int
+	contextFunc            ContextFunc
+	contextTypes           []reflect.Type
+	spanContext            *SpanContext
+	scalarMapValuesToSlice bool
+	rejectNull             bool
+	spreadSlice            bool
+	ignoreExtraArguments   bool
+	zeroFill               bool
+	checkContext           bool
+	requiredFields         bool
+	strictArrayLength      bool
+	keywords               []string
+	injected               []reflect.Value
+	rejectDuplicateKeys    bool
+	closeOnError           bool
+	optionalStructs        map[int]bool
+	positionalStructs      map[int]bool
+	coerceScalarToSlice    bool
+	streamingDecode        bool
+	allowNonFiniteFloats   bool
+	elementErrors          bool
+	maxDepth               int
+	resultTransform        func([]reflect.Value) ([]reflect.Value, error)
+	asyncCancellation      bool
+	strictIntegers         bool
+	codec                  Codec
+	paramsKey              string
+	multiError             bool
+	coerceBool             bool
+	paramOptions           map[int][]Option
+	nonNilPointers         bool
+	objectAsSingleArg      bool
+	syntax                 func(string) (string, error)
+	frameType              reflect.Type
+	frame                  *argFrame
+	abandoned              bool
+	observer               Observer
+	name                   string
+	resultKeyTransform     func(string) string
+	indent                 string
+	nilSlicesAsEmpty       bool
+	keyTransform           func(string) string
+	validators             []Validator
+	decoderFuncs           []func(*json.Decoder)
+	preDecoders            []func(int, json.RawMessage) error
+	maxBodySize            int64
+	contentCodecs          map[string]Codec
+	method                 bool
 }
 
 // defaultContextFunc is the default context function.
@@ -24,19 +78,72 @@ func defaultContextFunc() context.Context {
 }
 
 // ErrNotFunction is returned when a non-function value is passed.
-var ErrNotFunction = errors.New("Must pass a function")
+var ErrNotFunction error = newCallError(http.StatusInternalServerError, "not_function", "Must pass a function")
 
 // ErrTooManyArguments is returned when too many arguments are passed.
-var ErrTooManyArguments = errors.New("Too many arguments passed")
+var ErrTooManyArguments error = newCallError(http.StatusBadRequest, "too_many_arguments", "Too many arguments passed")
 
 // ErrTooFewArguments is returned when too few arguments are passed.
-var ErrTooFewArguments = errors.New("Too few arguments passed")
+var ErrTooFewArguments error = newCallError(http.StatusBadRequest, "too_few_arguments", "Too few arguments passed")
+
+// ErrExpectedArray is returned when the input is valid JSON, but not an array.
+var ErrExpectedArray error = newCallError(http.StatusBadRequest, "expected_array", "Arguments must be a JSON array")
+
+// ErrContextType is returned when the context is not assignable to the context parameter.
+var ErrContextType error = newCallError(http.StatusInternalServerError, "context_type", "Context is not assignable to the context parameter")
+
+// ErrReceiverType is returned when the receiver does not match a method expression.
+var ErrReceiverType error = newCallError(http.StatusInternalServerError, "receiver_type", "Receiver is not assignable to the method receiver")
+
+// ErrUnexportedMethod is returned when an unexported method is passed.
+var ErrUnexportedMethod error = newCallError(http.StatusInternalServerError, "unexported_method", "Method must be exported")
+
+// ErrMultipleContexts is returned when a function has more than one context parameter.
+var ErrMultipleContexts error = newCallError(http.StatusInternalServerError, "multiple_contexts", "Functions may have at most one context parameter")
+
+// ErrUnsupportedParameterType is returned when a parameter can't be decoded
+// from JSON, such as a channel or function.
+var ErrUnsupportedParameterType error = newCallError(http.StatusInternalServerError, "unsupported_parameter_type", "Unsupported parameter type")
+
+// ErrOutOfRange is returned when an integer is out of range of its parameter's type.
+var ErrOutOfRange error = newCallError(http.StatusBadRequest, "out_of_range", "Value out of range")
+
+// ErrArgumentType is returned when a Go value passed to CallFuncValues is not
+// assignable to its parameter.
+var ErrArgumentType error = newCallError(http.StatusBadRequest, "incorrect_type", "Incorrect argument type")
 
 // ErrInvalidJSON is returned when the input is malformed.
-var ErrInvalidJSON = errors.New("Invalid JSON")
+var ErrInvalidJSON error = newCallError(http.StatusBadRequest, "invalid_json", "Invalid JSON")
+
+// ErrArrayLength is returned when an array's length does not match a fixed-size array parameter.
+var ErrArrayLength error = newCallError(http.StatusBadRequest, "array_length", "Incorrect array length")
+
+// ErrNotInteger is returned when a number which is not whole is passed for an integer parameter.
+var ErrNotInteger error = newCallError(http.StatusBadRequest, "not_integer", "Expected an integer")
+
+// ErrInjectedType is returned when an injected value is not assignable to its parameter.
+var ErrInjectedType error = newCallError(http.StatusInternalServerError, "injected_type", "Injected value does not match parameter type")
+
+// ErrNullPointer is returned when null is passed for a pointer parameter which must be non-nil.
+var ErrNullPointer error = newCallError(http.StatusBadRequest, "null_pointer", "required, got null")
+
+// ErrObjectArgument is returned when an object is passed as the arguments of a function without exactly one parameter.
+var ErrObjectArgument error = newCallError(http.StatusBadRequest, "object_argument", "Objects may only be passed as the arguments of functions with a single parameter")
+
+// ErrNullNotAllowed is returned when null is passed for a non-nilable parameter.
+var ErrNullNotAllowed error = newCallError(http.StatusBadRequest, "null_not_allowed", "null not allowed for non-pointer parameter")
+
+// ErrErrorNotLast is returned when a function has an error result other than its last.
+var ErrErrorNotLast error = newCallError(http.StatusInternalServerError, "error_not_last", "Error results must be last")
+
+// ErrResultCount is returned when a function must return exactly one non-error result.
+var ErrResultCount error = newCallError(http.StatusInternalServerError, "result_count", "Function must return exactly one non-error result")
 
-// errVariadic is returned when a variadic function is used.
-var errVariadic = errors.New("Variadic functions are not yet supported")
+// ErrResultType is returned when a result is not assignable to the output pointer.
+var ErrResultType error = newCallError(http.StatusInternalServerError, "result_type", "Result is not assignable to the output")
+
+// ErrMaxDepthExceeded is returned when arguments are nested more deeply than WithMaxDepth allows.
+var ErrMaxDepthExceeded error = newCallError(http.StatusBadRequest, "max_depth_exceeded", "Maximum nesting depth exceeded")
 
 // UnmarshalError is an unmarshal error.
 type UnmarshalError json.UnmarshalTypeError
@@ -46,173 +153,2013 @@ func (e UnmarshalError) Error() string {
 	return fmt.Sprintf("Incorrect type %s, expected %s", e.Value, typeName(e.Type))
 }
 
-// ContextFunc is used to create a new context.
+// Code returns the suggested HTTP status code.
+func (e UnmarshalError) Code() int {
+	return http.StatusBadRequest
+}
+
+// Category returns the error category.
+func (e UnmarshalError) Category() string {
+	return "incorrect_type"
+}
+
+// MarshalJSON implementation.
+func (e UnmarshalError) MarshalJSON() ([]byte, error) {
+	return marshalError(e)
+}
+
+// unmarshalError returns an UnmarshalError wrapped in an *ArgumentError for
+// the argument at index i.
+func unmarshalError(i int, e UnmarshalError) error {
+	return &ArgumentError{Index: i, Err: e}
+}
+
+// ArgumentError is an error relating to the argument at the given index.
+type ArgumentError struct {
+	Index int
+	Err   error
+}
+
+// Error implementation. Type errors are reported by the UnmarshalError's own
+// message, which doesn't include the index.
+func (e *ArgumentError) Error() string {
+	if u, ok := e.Err.(UnmarshalError); ok {
+		return u.Error()
+	}
+	return fmt.Sprintf("Argument %d: %s", e.Index, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ArgumentError) Unwrap() error {
+	return e.Err
+}
+
+// Code returns the suggested HTTP status code of the underlying error,
+// defaulting to http.StatusBadRequest.
+func (e *ArgumentError) Code() int {
+	var c coder
+	if errors.As(e.Err, &c) {
+		return c.Code()
+	}
+	return http.StatusBadRequest
+}
+
+// Category returns the category of the underlying error, defaulting to "invalid_argument".
+func (e *ArgumentError) Category() string {
+	var c categorizer
+	if errors.As(e.Err, &c) {
+		return c.Category()
+	}
+	return "invalid_argument"
+}
+
+// MarshalJSON implementation.
+func (e *ArgumentError) MarshalJSON() ([]byte, error) {
+	return marshalError(e)
+}
+
+// ElementError is an error relating to an element of the slice or array
+// argument at the given index.
+type ElementError struct {
+	Index   int
+	Element int
+	Err     error
+}
+
+// Error implementation.
+func (e *ElementError) Error() string {
+	return fmt.Sprintf("Argument %d, element %d: %s", e.Index, e.Element, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ElementError) Unwrap() error {
+	return e.Err
+}
+
+// Code returns the suggested HTTP status code of the underlying error,
+// defaulting to http.StatusBadRequest.
+func (e *ElementError) Code() int {
+	var c coder
+	if errors.As(e.Err, &c) {
+		return c.Code()
+	}
+	return http.StatusBadRequest
+}
+
+// Category returns the category of the underlying error, defaulting to "invalid_argument".
+func (e *ElementError) Category() string {
+	var c categorizer
+	if errors.As(e.Err, &c) {
+		return c.Category()
+	}
+	return "invalid_argument"
+}
+
+// MarshalJSON implementation.
+func (e *ElementError) MarshalJSON() ([]byte, error) {
+	return marshalError(e)
+}
+
+// ContextFunc is used to create a new context. A nil context falls back to
+// context.Background.
 type ContextFunc func() context.Context
 
+// Validator is used to validate a decoded argument.
+type Validator func(paramIndex int, v reflect.Value) error
+
+// Observer is invoked after each call with the function name, the call's
+// duration and its resulting error.
+type Observer func(name string, dur time.Duration, err error)
+
 // Option function.
 type Option func(*config)
 
+// CombineOptions returns an option which applies the given options in order,
+// so that a base set may be assembled once and passed around as one. Later
+// options, including those given alongside the result, take precedence.
+func CombineOptions(options ...Option) Option {
+	options = append([]Option(nil), options...)
+	return func(v *config) {
+		for _, o := range options {
+			o(v)
+		}
+	}
+}
+
 // WithContextFunc sets the context function, used to create a new context
-// when the function being called expects one.
+// when the function being called expects one. When fn is nil or returns nil
+// context.Background is used instead.
 func WithContextFunc(fn ContextFunc) Option {
 	return func(v *config) {
 		v.contextFunc = fn
 	}
 }
 
-// newConfig returns a new config with options applied.
-func newConfig(options []Option) *config {
-	var c config
-	c.contextFunc = defaultContextFunc
-	for _, o := range options {
-		o(&c)
+// WithContext sets the context passed to functions which expect one. When
+// combined with WithContextFunc the last option given wins.
+func WithContext(ctx context.Context) Option {
+	return func(v *config) {
+		v.contextFunc = func() context.Context {
+			return ctx
+		}
 	}
-	return &c
 }
 
-// Normalize returns a normalized json array string, to be used as parameters.
-func Normalize(s string) string {
-	s = strings.TrimSpace(s)
-	if len(s) > 0 && s[0] == '[' {
-		return s
+// WithContextTypes sets additional parameter types which are injected with
+// the context, such as framework-specific request context interfaces. The
+// context function must return a value assignable to each of these types.
+func WithContextTypes(types ...reflect.Type) Option {
+	return func(v *config) {
+		v.contextTypes = append(v.contextTypes, types...)
 	}
-	return "[" + s + "]"
 }
 
-// CallFunc invokes a function with arguments derived from a json string.
-func CallFunc(fn interface{}, args string, options ...Option) ([]reflect.Value, error) {
-	t := reflect.TypeOf(fn)
+// WithValidator adds a function invoked with each decoded argument, allowing
+// callers to reject invalid values such as unknown enum constants. Errors are
+// returned as an *ArgumentError.
+func WithValidator(fn Validator) Option {
+	return func(v *config) {
+		v.validators = append(v.validators, fn)
+	}
+}
 
-	arguments, err := ArgumentsOfFunc(t, args, options...)
-	if err != nil {
-		return nil, err
+// WithPreDecode adds a function invoked with the raw JSON of each parameter in
+// order, before any coercion or decoding, for example to audit or gatekeep
+// arguments. Errors abort the call and are returned as an *ArgumentError.
+func WithPreDecode(fn func(index int, raw json.RawMessage) error) Option {
+	return func(v *config) {
+		v.preDecoders = append(v.preDecoders, fn)
 	}
+}
 
-	return CallFuncArgs(fn, arguments, options...)
+// WithScalarMapValuesToSlice wraps scalar values into single-element slices
+// when decoding map[string][]T parameters, so {"k":"v"} decodes as {"k":["v"]}.
+func WithScalarMapValuesToSlice() Option {
+	return func(v *config) {
+		v.scalarMapValuesToSlice = true
+	}
 }
 
-// CallMethod invokes a method on a struct with arguments derived from a json string.
-func CallMethod(receiver interface{}, m reflect.Method, args string, options ...Option) ([]reflect.Value, error) {
-	arguments, err := ArgumentsOfMethod(m, args, options...)
-	if err != nil {
-		return nil, err
+// context returns a new context for injection, falling back to
+// context.Background when the context function is nil or returns nil.
+func (c *config) context() context.Context {
+	var ctx context.Context
+	if c.contextFunc != nil {
+		ctx = c.contextFunc()
 	}
 
-	return CallMethodArgs(receiver, m, arguments, options...)
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if c.spanContext != nil {
+		ctx = context.WithValue(ctx, SpanContextKey, *c.spanContext)
+	}
+	return ctx
 }
 
-// CallFuncArgs invokes a function with arguments derived from a json string.
-func CallFuncArgs(fn interface{}, args []reflect.Value, options ...Option) (values []reflect.Value, err error) {
-	// invoke
-	res := reflect.ValueOf(fn).Call(args)
+// isContext returns true if the given parameter type is injected with the context.
+func (c *config) isContext(t reflect.Type) bool {
+	for _, ct := range c.contextTypes {
+		if t == ct {
+			return true
+		}
+	}
+	return isContext(t)
+}
 
-	// results
-	for _, v := range res {
-		if isError(v.Type()) && v.IsValid() && !v.IsNil() {
-			return nil, v.Interface().(error)
+// params returns the types of the parameters consumed from JSON, excluding
+// the receiver, any context and any injected values.
+func (c *config) params(t reflect.Type) []reflect.Type {
+	var types []reflect.Type
+	skip := len(c.injected)
+	for i := paramOffset(c.method); i < t.NumIn(); i++ {
+		switch {
+		case c.isContext(t.In(i)):
+		case skip > 0:
+			skip--
+		default:
+			types = append(types, t.In(i))
 		}
-		values = append(values, v)
 	}
+	return types
+}
 
-	return
+// arity returns the number of parameters consumed from JSON.
+func (c *config) arity(t reflect.Type) int {
+	n := 0
+	skip := len(c.injected)
+	for i := paramOffset(c.method); i < t.NumIn(); i++ {
+		switch {
+		case c.isContext(t.In(i)):
+		case skip > 0:
+			skip--
+		default:
+			n++
+		}
+	}
+	return n
 }
 
-// CallMethodArgs invokes a method on a struct with arguments derived from a json string.
-func CallMethodArgs(receiver interface{}, m reflect.Method, args []reflect.Value, options ...Option) (values []reflect.Value, err error) {
-	// receiver
-	r := reflect.ValueOf(receiver)
-	args = append([]reflect.Value{r}, args...)
+// contexts returns the number of context parameters.
+func (c *config) contexts(t reflect.Type) int {
+	n := 0
+	for i := paramOffset(c.method); i < t.NumIn(); i++ {
+		if c.isContext(t.In(i)) {
+			n++
+		}
+	}
+	return n
+}
 
-	// invoke
-	res := m.Func.Call(args)
+// WithDecoderConfig adds a function used to configure the decoder of each
+// argument, for example to enable UseNumber or DisallowUnknownFields.
+func WithDecoderConfig(fn func(*json.Decoder)) Option {
+	return func(v *config) {
+		v.decoderFuncs = append(v.decoderFuncs, fn)
+	}
+}
 
-	// results
-	for _, v := range res {
-		if isError(v.Type()) && v.IsValid() && !v.IsNil() {
-			return nil, v.Interface().(error)
-		}
-		values = append(values, v)
+// WithKeyTransform sets a function used to rewrite the object keys of struct
+// parameters before decoding, including the keys of nested structs. Keys of
+// objects decoding into maps or interfaces are left as-is. This is useful for
+// conventions which json tags can't express, such as mapping "user_name" to
+// the UserName field of untagged structs.
+func WithKeyTransform(fn func(string) string) Option {
+	return func(v *config) {
+		v.keyTransform = fn
 	}
+}
 
-	return
+// WithObjectAsSingleArg treats a top-level object, such as {"a": 1}, as the
+// only argument of functions with exactly one parameter excluding the
+// receiver and any context, for example a struct or map. Objects passed to
+// other functions return ErrObjectArgument rather than ErrExpectedArray.
+func WithObjectAsSingleArg() Option {
+	return func(v *config) {
+		v.objectAsSingleArg = true
+	}
 }
 
-// ArgumentsOfMethod returns arguments for the given method, derived from a json string.
-func ArgumentsOfMethod(m reflect.Method, args string, options ...Option) ([]reflect.Value, error) {
-	c := newConfig(options)
-	c.arity = m.Type.NumIn() - 1
-	c.offset = 1
-	c.contextIndex = 1
-	return arguments(m.Type, args, c)
+// objectArgument wraps a top-level object in an array when it is the single
+// argument of a function of the given arity, returning the offset of s within
+// the result.
+func (c *config) objectArgument(s string, arity int) (string, int, error) {
+	if !c.objectAsSingleArg || jsonKind(json.RawMessage(s)) != "object" {
+		return s, 0, nil
+	}
+
+	if arity != 1 {
+		return "", 0, ErrObjectArgument
+	}
+
+	return "[" + s + "]", 1, nil
 }
 
-// ArgumentsOfFunc returns arguments for the given function, derived from a json string.
-func ArgumentsOfFunc(t reflect.Type, args string, options ...Option) ([]reflect.Value, error) {
-	if t.Kind() != reflect.Func {
-		return nil, ErrNotFunction
+// WithNonNilPointers rejects null for pointer parameters, such as *User, for
+// handlers which require them to be present. Maps, slices and interfaces may
+// still be null, see WithRejectNull for other parameters.
+func WithNonNilPointers() Option {
+	return func(v *config) {
+		v.nonNilPointers = true
 	}
-	c := newConfig(options)
-	c.arity = t.NumIn()
-	return arguments(t, args, c)
 }
 
-// arguments implementation.
-func arguments(t reflect.Type, s string, c *config) ([]reflect.Value, error) {
-	var args []reflect.Value
+// WithRejectNull rejects null for parameters which cannot be nil, such as
+// structs and numbers, which would otherwise silently decode as zero values.
+func WithRejectNull() Option {
+	return func(v *config) {
+		v.rejectNull = true
+	}
+}
 
-	// ensure it's not variadic
-	if t.IsVariadic() {
-		return nil, errVariadic
+// WithSpreadSlice allows the elements of a final slice parameter to be passed
+// as trailing arguments. By default a slice is a single argument, so
+// func(nums []int) expects [[1,2,3]], whereas with this option [1,2,3] is also
+// accepted. When exactly one array is passed for the final parameter it's
+// treated as the slice itself, so [[1,2]] for a [][]int is not spread.
+func WithSpreadSlice() Option {
+	return func(v *config) {
+		v.spreadSlice = true
 	}
+}
 
-	// inject context
-	if hasContext(t, c.contextIndex) {
-		args = append(args, reflect.ValueOf(c.contextFunc()))
-		c.offset++
-		c.arity--
+// decode decodes a raw value into v using the configured decoder.
+func (c *config) decode(raw json.RawMessage, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	for _, fn := range c.decoderFuncs {
+		fn(dec)
 	}
+	return dec.Decode(v)
+}
 
-	// parse params
-	var params []json.RawMessage
+// WithIgnoreExtraArguments ignores arguments beyond the number of parameters
+// instead of returning an error, which is useful while rolling out a change
+// removing a parameter. Too few arguments remains an error.
+func WithIgnoreExtraArguments() Option {
+	return func(v *config) {
+		v.ignoreExtraArguments = true
+	}
+}
 
-	err := json.Unmarshal([]byte(s), &params)
+// WithZeroFill pads missing trailing arguments with the zero values of their
+// parameters instead of returning ErrTooFewArguments, mirroring the calling
+// conventions of dynamic languages. Too many arguments remains an error unless
+// combined with WithIgnoreExtraArguments.
+func WithZeroFill() Option {
+	return func(v *config) {
+		v.zeroFill = true
+	}
+}
 
-	if _, ok := err.(*json.SyntaxError); ok {
-		return nil, ErrInvalidJSON
+// WithCheckContext skips invocation when the injected context is already
+// cancelled or expired, returning the context's error instead.
+func WithCheckContext() Option {
+	return func(v *config) {
+		v.checkContext = true
 	}
+}
 
-	if err != nil {
-		return nil, err
+// WithAsyncCancellation invokes the function in a goroutine, returning the
+// injected context's error as soon as it is cancelled or expires. The
+// function itself still runs to completion in the background and its results
+// are discarded, so it should observe the context to stop early. Calls
+// without an injected context are invoked synchronously.
+func WithAsyncCancellation() Option {
+	return func(v *config) {
+		v.asyncCancellation = true
 	}
+}
 
-	// too few
-	if len(params) < c.arity {
-		return nil, ErrTooFewArguments
+// WithMultiError treats a trailing []error result, or one implementing
+// Unwrap() []error, as the call's error when it holds any non-nil errors.
+// Slices are joined with errors.Join, while aggregates are returned as-is when
+// they implement error. Without it such results are ordinary values.
+func WithMultiError() Option {
+	return func(v *config) {
+		v.multiError = true
 	}
+}
 
-	// too many
-	if len(params) > c.arity {
-		return nil, ErrTooManyArguments
+// WithCoerceBool decodes the numbers 0 and 1 as false and true for boolean
+// parameters, as some clients and SQLite-backed configs represent booleans.
+// Other numbers remain an error.
+func WithCoerceBool() Option {
+	return func(v *config) {
+		v.coerceBool = true
 	}
+}
 
-	// process the arguments
-	for i := 0; i < c.arity; i++ {
-		kind := t.In(c.offset + i)
-		arg := reflect.New(kind)
-		value := arg.Interface()
+// WithStrictIntegers errors with an *IntegerError when a number which is not
+// whole, such as 1.5 or 1e-1, is passed for an integer parameter. Whole
+// numbers written with a fraction or exponent, such as 1.0 or 1e2, are
+// accepted. Only the parameter itself is checked, not nested values.
+func WithStrictIntegers() Option {
+	return func(v *config) {
+		v.strictIntegers = true
+	}
+}
 
-		err := json.Unmarshal(params[i], value)
+// WithStrictArrayLength errors when the JSON array for a fixed-size array
+// parameter such as [3]int has a different length, rather than leaving
+// trailing zero values or dropping extra elements. Only the parameter itself
+// is checked, not nested arrays.
+func WithStrictArrayLength() Option {
+	return func(v *config) {
+		v.strictArrayLength = true
+	}
+}
 
-		if e, ok := err.(*json.UnmarshalTypeError); ok {
-			return nil, UnmarshalError(*e)
+// WithParamOptions applies options to the parameter at the given index,
+// excluding the receiver and any context, merged over those of the call. For
+// example WithRejectNull or WithDecoderConfig may be used for a single
+// parameter. Only options which affect decoding have an effect.
+func WithParamOptions(index int, options ...Option) Option {
+	return func(v *config) {
+		if v.paramOptions == nil {
+			v.paramOptions = make(map[int][]Option)
 		}
+		v.paramOptions[index] = append(v.paramOptions[index], options...)
+	}
+}
 
-		if err != nil {
-			return nil, err
+// paramConfig returns the config for decoding the parameter at index i.
+func (c *config) paramConfig(i int) *config {
+	options, ok := c.paramOptions[i]
+	if !ok {
+		return c
+	}
+
+	p := *c
+	p.paramOptions = nil
+	p.decoderFuncs = c.decoderFuncs[:len(c.decoderFuncs):len(c.decoderFuncs)]
+	p.validators = c.validators[:len(c.validators):len(c.validators)]
+	p.preDecoders = c.preDecoders[:len(c.preDecoders):len(c.preDecoders)]
+
+	if c.optionalStructs != nil {
+		p.optionalStructs = make(map[int]bool, len(c.optionalStructs))
+		for k, v := range c.optionalStructs {
+			p.optionalStructs[k] = v
 		}
+	}
 
-		args = append(args, arg.Elem())
+	if c.positionalStructs != nil {
+		p.positionalStructs = make(map[int]bool, len(c.positionalStructs))
+		for k, v := range c.positionalStructs {
+			p.positionalStructs[k] = v
+		}
 	}
 
-	return args, nil
+	for _, o := range options {
+		o(&p)
+	}
+
+	return &p
+}
+
+// WithOptionalStruct marks the struct parameter at the given index, excluding
+// the receiver and any context, as optional. Trailing optional structs may be
+// omitted from the JSON, such as ["value"] for func(string, Options), in which
+// case they receive their zero value. When present they decode as usual.
+func WithOptionalStruct(index int) Option {
+	return func(v *config) {
+		if v.optionalStructs == nil {
+			v.optionalStructs = make(map[int]bool)
+		}
+		v.optionalStructs[index] = true
+	}
+}
+
+// WithStructFromPositional allows the struct parameter at the given index,
+// excluding the receiver and any context, to be given as an array of its
+// exported fields in declaration order, bridging positional callers to
+// message-style handlers such as func(req AddRequest). For example
+// [[1, 2]] fills the two fields of AddRequest, while objects decode as usual.
+// When the struct is the final parameter its fields may also be passed as
+// trailing arguments, such as [1, 2]. Arrays which don't match the number of
+// fields return an *ArrayLengthError, and fields which fail to decode an
+// *ElementError.
+func WithStructFromPositional(index int) Option {
+	return func(v *config) {
+		if v.positionalStructs == nil {
+			v.positionalStructs = make(map[int]bool)
+		}
+		v.positionalStructs[index] = true
+	}
+}
+
+// WithCoerceScalarToSlice wraps non-array values passed to slice parameters
+// into single-element slices, so ["Tobi"] may be passed for a []string
+// parameter. The value must match the element type, otherwise the usual type
+// error is returned. Byte slices are excluded as they decode from strings.
+func WithCoerceScalarToSlice() Option {
+	return func(v *config) {
+		v.coerceScalarToSlice = true
+	}
+}
+
+// WithStreamingDecode reads and decodes params one at a time rather than
+// parsing them all up front, reducing peak memory for large argument arrays.
+// Arity is checked by counting params as they're read, and is reported before
+// any decoding error. It has no effect when combined with WithSpreadSlice or
+// WithTrailingKeywords, which need every param at once.
+func WithStreamingDecode() Option {
+	return func(v *config) {
+		v.streamingDecode = true
+	}
+}
+
+// WithAllowNonFiniteFloats accepts the strings "NaN", "Infinity" and
+// "-Infinity" for float parameters, which JSON numbers can't represent. When
+// marshaling results, non-finite float results are written as the same
+// strings rather than failing. Only the parameters and results themselves are
+// affected, not floats nested within them.
+func WithAllowNonFiniteFloats() Option {
+	return func(v *config) {
+		v.allowNonFiniteFloats = true
+	}
+}
+
+// WithElementErrors reports which element of a slice or array argument failed
+// to decode, returning an *ElementError such as "Argument 0, element 1:
+// Incorrect type string, expected number". Elements are only decoded
+// individually once the argument as a whole has failed.
+func WithElementErrors() Option {
+	return func(v *config) {
+		v.elementErrors = true
+	}
+}
+
+// WithMaxDepth rejects arguments whose arrays and objects are nested more
+// than n levels deep with ErrMaxDepthExceeded, before they are decoded. The
+// params array itself counts as one level, so [[1]] has a depth of two.
+func WithMaxDepth(n int) Option {
+	return func(v *config) {
+		v.maxDepth = n
+	}
+}
+
+// WithInjected sets values passed to the leading non-context parameters, for
+// dependencies such as a *Session or *DB which are not supplied by the JSON.
+// Each value must be assignable to its parameter, otherwise ErrInjectedType
+// is returned.
+func WithInjected(values ...reflect.Value) Option {
+	return func(v *config) {
+		v.injected = values
+	}
+}
+
+// WithCloseOnError closes any io.Closer results, such as the *os.File of
+// a (*os.File, error) result, when the function returns a non-nil error.
+// Results are closed before the call returns, and since no results are
+// returned alongside an error the caller never receives a closed value.
+// Errors from Close are ignored.
+func WithCloseOnError() Option {
+	return func(v *config) {
+		v.closeOnError = true
+	}
+}
+
+// WithResultTransform sets a function invoked with the results of each
+// successful call, whose return values replace them, for example to wrap
+// results in a response envelope. It is not invoked when the call errors.
+func WithResultTransform(fn func([]reflect.Value) ([]reflect.Value, error)) Option {
+	return func(v *config) {
+		v.resultTransform = fn
+	}
+}
+
+// WithObserver sets a function invoked after each call completes, for
+// recording metrics such as latency. Methods are reported by their method
+// name, registry functions by their registered name, and other functions by
+// their fully-qualified function name.
+func WithObserver(fn Observer) Option {
+	return func(v *config) {
+		v.observer = fn
+	}
+}
+
+// withName sets the name reported to observers.
+func withName(name string) Option {
+	return func(v *config) {
+		v.name = name
+	}
+}
+
+// newConfig returns a new config with options applied.
+func newConfig(options []Option) *config {
+	var c config
+	c.contextFunc = defaultContextFunc
+	for _, o := range options {
+		o(&c)
+	}
+	return &c
+}
+
+// Normalize returns a normalized json array string, to be used as parameters.
+func Normalize(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) > 0 && s[0] == '[' {
+		return s
+	}
+	return "[" + s + "]"
+}
+
+// NormalizeStrict returns a normalized json array string as Normalize does,
+// or ErrInvalidJSON when the result is not a single valid JSON value, such
+// as when trailing content follows an array or payloads are concatenated.
+func NormalizeStrict(s string) (string, error) {
+	s = Normalize(s)
+	if !json.Valid([]byte(s)) {
+		return "", ErrInvalidJSON
+	}
+	return s, nil
+}
+
+// NormalizeArgs returns a json array string from command-line tokens. Tokens
+// which are valid JSON, such as numbers, booleans, objects, arrays and quoted
+// strings, are passed through; all other tokens become JSON strings. For
+// example ["1", "hello", "true"] produces [1,"hello",true].
+func NormalizeArgs(args []string) string {
+	var b strings.Builder
+	b.WriteByte('[')
+	for i, arg := range args {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+
+		if json.Valid([]byte(arg)) {
+			b.WriteString(strings.TrimSpace(arg))
+			continue
+		}
+
+		s, _ := json.Marshal(arg)
+		b.Write(s)
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+// CallFunc invokes a function with arguments derived from a json string.
+//
+// Bound method values such as s.Sum are regular functions and may be passed
+// directly. Method expressions such as (*Service).Sum take the receiver as the
+// first parameter, use CallMethodExpr for those.
+//
+// On success all results are returned in order, including nil error values,
+// so a function returning only an error yields a single nil value. Functions
+// without results yield no values. Use CallVoid when only the error matters.
+func CallFunc(fn interface{}, args string, options ...Option) ([]reflect.Value, error) {
+	return callPooled(reflect.ValueOf(fn), args, newConfig(options))
+}
+
+// callPooled invokes fn with arguments derived from a json string, reusing
+// the storage of its arguments once the call completes.
+func callPooled(fn reflect.Value, args string, c *config) ([]reflect.Value, error) {
+	if fn.Kind() != reflect.Func {
+		return nil, ErrNotFunction
+	}
+
+	c.useFrame(fn.Type())
+	defer c.releaseFrame()
+
+	arguments, err := arguments(fn.Type(), args, c)
+	if err != nil {
+		return nil, err
+	}
+
+	return call(fn, arguments, c)
+}
+
+// CallFuncCtx invokes a function as CallFunc does, additionally returning
+// the context which was injected, for example to log its trace ID after the
+// call. The context is nil if the function does not accept one.
+func CallFuncCtx(fn interface{}, args string, options ...Option) (context.Context, []reflect.Value, error) {
+	if reflect.ValueOf(fn).Kind() != reflect.Func {
+		return nil, nil, ErrNotFunction
+	}
+
+	t := reflect.TypeOf(fn)
+
+	arguments, err := ArgumentsOfFunc(t, args, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx := injectedContext(t, 0, arguments, newConfig(options))
+	values, err := CallFuncArgs(fn, arguments, options...)
+	return ctx, values, err
+}
+
+// CallFuncCapture invokes a function such as func(w io.Writer, name string)
+// error, passing a buffer for its leading io.Writer parameter and returning
+// the bytes written to it. The buffer is only injected when the first
+// non-context parameter is exactly io.Writer, otherwise the function is
+// called as usual and no bytes are returned.
+func CallFuncCapture(fn interface{}, args string, options ...Option) ([]byte, error) {
+	var buf bytes.Buffer
+
+	t := reflect.TypeOf(fn)
+	if t != nil && t.Kind() == reflect.Func {
+		c := newConfig(options)
+		for i := 0; i < t.NumIn(); i++ {
+			if c.isContext(t.In(i)) {
+				continue
+			}
+
+			if t.In(i) == writerInterface {
+				options = append(options[:len(options):len(options)], withWriter(&buf))
+			}
+			break
+		}
+	}
+
+	_, err := CallFunc(fn, args, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// withWriter injects w before any other injected values.
+func withWriter(w io.Writer) Option {
+	return func(v *config) {
+		v.injected = append([]reflect.Value{reflect.ValueOf(&w).Elem()}, v.injected...)
+	}
+}
+
+// CallVoid invokes a function with arguments derived from a json string,
+// returning only its error. It is intended for side-effecting functions which
+// return nothing or only an error, any other results are discarded.
+func CallVoid(fn interface{}, args string, options ...Option) error {
+	_, err := CallFunc(fn, args, options...)
+	return err
+}
+
+// CallFuncInto invokes a function with arguments derived from a json string,
+// assigning its single non-error result to the value pointed to by out. The
+// function is not invoked when it has any other number of non-error results,
+// returning ErrResultCount, or when the result type is not assignable to out,
+// returning ErrResultType. Interface results are checked once returned.
+func CallFuncInto(fn interface{}, args string, out interface{}, options ...Option) error {
+	t := reflect.TypeOf(fn)
+	if t == nil || t.Kind() != reflect.Func {
+		return ErrNotFunction
+	}
+
+	o := reflect.ValueOf(out)
+	if o.Kind() != reflect.Ptr || o.IsNil() {
+		return ErrResultType
+	}
+	o = o.Elem()
+
+	index := -1
+	for i := 0; i < t.NumOut(); i++ {
+		if isError(t.Out(i)) {
+			continue
+		}
+
+		if index >= 0 {
+			return ErrResultCount
+		}
+		index = i
+	}
+
+	if index < 0 {
+		return ErrResultCount
+	}
+
+	if rt := t.Out(index); !rt.AssignableTo(o.Type()) && rt.Kind() != reflect.Interface {
+		return ErrResultType
+	}
+
+	values, err := CallFunc(fn, args, options...)
+	if err != nil {
+		return err
+	}
+
+	v := values[index]
+	if v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			o.Set(reflect.Zero(o.Type()))
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	if !v.Type().AssignableTo(o.Type()) {
+		return ErrResultType
+	}
+
+	o.Set(v)
+	return nil
+}
+
+// Validate returns nil when the arguments derived from a json string would
+// decode successfully for the given function, or the decoding error otherwise.
+// The function itself is never invoked.
+func Validate(fn interface{}, args string, options ...Option) error {
+	if reflect.ValueOf(fn).Kind() != reflect.Func {
+		return ErrNotFunction
+	}
+
+	_, err := ArgumentsOfFunc(reflect.TypeOf(fn), args, options...)
+	return err
+}
+
+// CheckFunc returns nil when fn has a shape which may be called from JSON, or
+// an error describing why not, so that programmer errors may be caught at
+// startup rather than on the first call. Functions may accept at most one
+// context, may only return an error as their last result, and may not accept
+// parameters which can't be decoded from JSON, such as channels.
+func CheckFunc(fn interface{}, options ...Option) error {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func {
+		return ErrNotFunction
+	}
+
+	t := v.Type()
+	c := newConfig(options)
+	if err := c.checkFunc(t); err != nil {
+		return err
+	}
+
+	for i := 0; i < t.NumOut()-1; i++ {
+		if isError(t.Out(i)) {
+			return ErrErrorNotLast
+		}
+	}
+
+	return nil
+}
+
+// CallMethod invokes a method on a struct with arguments derived from a json string.
+func CallMethod(receiver interface{}, m reflect.Method, args string, options ...Option) ([]reflect.Value, error) {
+	if m.PkgPath != "" {
+		return nil, ErrUnexportedMethod
+	}
+
+	c := newConfig(options)
+	c.method = true
+	c.useFrame(m.Type)
+	defer c.releaseFrame()
+
+	arguments, err := arguments(m.Type, args, c)
+	if err != nil {
+		return nil, err
+	}
+
+	return callMethod(receiver, m, arguments, c)
+}
+
+// CallMethodCtx invokes a method as CallMethod does, additionally returning
+// the context which was injected. The context is nil if the method does not
+// accept one.
+func CallMethodCtx(receiver interface{}, m reflect.Method, args string, options ...Option) (context.Context, []reflect.Value, error) {
+	arguments, err := ArgumentsOfMethod(m, args, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx := injectedContext(m.Type, 1, arguments, newConfig(options))
+	values, err := CallMethodArgs(receiver, m, arguments, options...)
+	return ctx, values, err
+}
+
+// CallMethodExpr invokes a method expression such as (*Service).Sum, passing
+// the receiver as the first parameter and deriving the remaining arguments,
+// including any context following the receiver, from a json string.
+func CallMethodExpr(fn interface{}, receiver interface{}, args string, options ...Option) ([]reflect.Value, error) {
+	t := reflect.TypeOf(fn)
+	if t == nil || t.Kind() != reflect.Func {
+		return nil, ErrNotFunction
+	}
+
+	if receiver == nil || t.NumIn() == 0 || !reflect.TypeOf(receiver).AssignableTo(t.In(0)) {
+		return nil, ErrReceiverType
+	}
+
+	m := reflect.Method{
+		Type: t,
+		Func: reflect.ValueOf(fn),
+	}
+
+	return CallMethod(receiver, m, args, options...)
+}
+
+// CallFuncArgs invokes a function with arguments derived from a json string.
+// The results are the values returned by the function, which don't reference
+// the arguments' storage and so remain valid after the call, see
+// Results.Detach for retaining copies.
+func CallFuncArgs(fn interface{}, args []reflect.Value, options ...Option) (values []reflect.Value, err error) {
+	return CallValueArgs(reflect.ValueOf(fn), args, options...)
+}
+
+// CallFuncValues invokes a function with Go values in place of the JSON
+// params of CallFunc, for callers bridging from sources other than JSON. The
+// context and any injected values are supplied as usual, and error results
+// are handled as CallFuncArgs does. Each value must be assignable to its
+// parameter, or nil for parameters which may be nil, otherwise an
+// *ArgumentError wrapping an *ArgumentTypeError is returned. Options which
+// affect decoding, such as validators, have no effect.
+func CallFuncValues(fn interface{}, args []interface{}, options ...Option) ([]reflect.Value, error) {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func {
+		return nil, ErrNotFunction
+	}
+
+	c := newConfig(options)
+	arguments, err := c.bindValues(v.Type(), args)
+	if err != nil {
+		return nil, err
+	}
+
+	return call(v, arguments, c)
+}
+
+// CallMethodArgs invokes a method on a struct with arguments derived from a json string.
+func CallMethodArgs(receiver interface{}, m reflect.Method, args []reflect.Value, options ...Option) (values []reflect.Value, err error) {
+	if m.PkgPath != "" {
+		return nil, ErrUnexportedMethod
+	}
+
+	return callMethod(receiver, m, args, newConfig(options))
+}
+
+// callMethod invokes a method with the given receiver and arguments.
+func callMethod(receiver interface{}, m reflect.Method, args []reflect.Value, c *config) ([]reflect.Value, error) {
+	r := reflect.ValueOf(receiver)
+	args = append([]reflect.Value{r}, args...)
+
+	if c.name == "" {
+		c.name = m.Name
+	}
+	return call(m.Func, args, c)
+}
+
+// CallValue invokes a function value, such as a bound method value, with
+// arguments derived from a json string.
+func CallValue(fn reflect.Value, args string, options ...Option) ([]reflect.Value, error) {
+	return callPooled(fn, args, newConfig(options))
+}
+
+// CallValueArgs invokes a function value with the given arguments.
+func CallValueArgs(fn reflect.Value, args []reflect.Value, options ...Option) ([]reflect.Value, error) {
+	c := newConfig(options)
+	return call(fn, args, c)
+}
+
+// call implementation.
+func call(fn reflect.Value, args []reflect.Value, c *config) (values []reflect.Value, err error) {
+	// observe
+	if c.observer != nil {
+		start := time.Now()
+		defer func() {
+			name := c.name
+			if name == "" {
+				name = funcName(fn)
+			}
+			c.observer(name, time.Since(start), err)
+		}()
+	}
+
+	// abandoned
+	if c.checkContext {
+		if err := contextErr(fn.Type(), args, c); err != nil {
+			return nil, err
+		}
+	}
+
+	// invoke
+	var res []reflect.Value
+	if ctx := injectedContext(fn.Type(), 0, args, c); c.asyncCancellation && ctx != nil {
+		res, err = callAsync(ctx, fn, args)
+		if err != nil {
+			c.abandoned = true
+			return nil, err
+		}
+	} else {
+		res = invoke(fn, args)
+	}
+
+	// results
+	for i, v := range res {
+		if err := c.resultError(v, i == len(res)-1); err != nil {
+			if c.closeOnError {
+				closeResults(res)
+			}
+			return nil, err
+		}
+		values = append(values, v)
+	}
+
+	// transform
+	if c.resultTransform != nil {
+		return c.resultTransform(values)
+	}
+
+	return
+}
+
+// resultError returns the error held by a result, if any.
+func (c *config) resultError(v reflect.Value, last bool) error {
+	if hasError(v) {
+		return v.Interface().(error)
+	}
+
+	if c.multiError && last {
+		return multiError(v)
+	}
+
+	return nil
+}
+
+// multiError returns the joined errors of a []error or Unwrap() []error
+// result, or nil when it holds none.
+func multiError(v reflect.Value) error {
+	if v.Type() == errorSliceType {
+		return errors.Join(v.Interface().([]error)...)
+	}
+
+	if !v.Type().Implements(multiUnwrapperInterface) || (isNilable(v.Type()) && v.IsNil()) {
+		return nil
+	}
+
+	u := v.Interface().(interface{ Unwrap() []error })
+	err := errors.Join(u.Unwrap()...)
+	if err == nil {
+		return nil
+	}
+
+	if e, ok := u.(error); ok {
+		return e
+	}
+
+	return err
+}
+
+// callAsync invokes fn in a goroutine, returning early with the context's
+// error when it is done first. Panics are re-raised in the caller unless the
+// call has been abandoned.
+func callAsync(ctx context.Context, fn reflect.Value, args []reflect.Value) ([]reflect.Value, error) {
+	type outcome struct {
+		res       []reflect.Value
+		recovered interface{}
+	}
+
+	done := make(chan outcome, 1)
+
+	go func() {
+		var o outcome
+		defer func() {
+			if r := recover(); r != nil {
+				o.recovered = r
+			}
+			done <- o
+		}()
+		o.res = invoke(fn, args)
+	}()
+
+	select {
+	case o := <-done:
+		if o.recovered != nil {
+			panic(o.recovered)
+		}
+		return o.res, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// invoke calls fn, passing the final argument of variadic functions as the
+// slice of variadic arguments.
+func invoke(fn reflect.Value, args []reflect.Value) []reflect.Value {
+	if fn.Type().IsVariadic() {
+		return fn.CallSlice(args)
+	}
+	return fn.Call(args)
+}
+
+// closeResults closes the non-nil io.Closer results other than errors.
+func closeResults(res []reflect.Value) {
+	for _, v := range res {
+		if isError(v.Type()) || !v.Type().Implements(closerInterface) {
+			continue
+		}
+
+		if isNilable(v.Type()) && v.IsNil() {
+			continue
+		}
+
+		v.Interface().(io.Closer).Close()
+	}
+}
+
+// ArgumentsOfMethod returns arguments for the given method, derived from a json string.
+func ArgumentsOfMethod(m reflect.Method, args string, options ...Option) ([]reflect.Value, error) {
+	if m.PkgPath != "" {
+		return nil, ErrUnexportedMethod
+	}
+
+	c := newConfig(options)
+	c.method = true
+	return arguments(m.Type, args, c)
+}
+
+// ArgumentsOfFunc returns arguments for the given function, derived from a json string.
+// Blank input is treated as an empty array, while other values which are not
+// arrays return ErrExpectedArray, use Normalize to accept those.
+func ArgumentsOfFunc(t reflect.Type, args string, options ...Option) ([]reflect.Value, error) {
+	if t == nil || t.Kind() != reflect.Func {
+		return nil, ErrNotFunction
+	}
+	c := newConfig(options)
+	return arguments(t, args, c)
+}
+
+// DecodeArgument returns a value of type t decoded from a single JSON value,
+// exactly as the corresponding argument of ArgumentsOfFunc would be, which is
+// useful for custom dispatch. The value is treated as the argument at index
+// 0, both for errors and WithParamOptions. ErrUnsupportedParameterType is
+// returned for a nil type or one which can't be decoded from JSON.
+func DecodeArgument(t reflect.Type, raw json.RawMessage, options ...Option) (reflect.Value, error) {
+	if t == nil {
+		return reflect.Value{}, ErrUnsupportedParameterType
+	}
+
+	if isUnsupported(t) {
+		return reflect.Value{}, &ArgumentError{Index: 0, Err: &ParameterTypeError{Kind: unrollPointer(t).Kind(), Type: t}}
+	}
+
+	c := newConfig(options)
+
+	if err := c.checkDepth(string(raw)); err != nil {
+		return reflect.Value{}, err
+	}
+
+	return c.paramConfig(0).decodeValue(0, t, raw)
+}
+
+// Span is the byte range of an argument within the arguments string.
+type Span struct {
+	Start int
+	End   int
+}
+
+// ArgumentsOfFuncMeta returns arguments for the given function, derived from a
+// json string, along with the span of each argument within the string. Spans
+// are returned on decoding errors as well, for highlighting the offending value.
+func ArgumentsOfFuncMeta(t reflect.Type, args string, options ...Option) ([]reflect.Value, []Span, error) {
+	if t == nil || t.Kind() != reflect.Func {
+		return nil, nil, ErrNotFunction
+	}
+
+	c := newConfig(options)
+	args, err := c.convert(args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := c.checkDepth(args); err != nil {
+		return nil, nil, err
+	}
+
+	args, offset, err := c.extractParams(blankAsEmpty(args))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	args, wrapped, err := c.objectArgument(args, c.arity(t))
+	if err != nil {
+		return nil, nil, err
+	}
+	offset -= wrapped
+
+	params, spans, err := scanParams(args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i := range spans {
+		spans[i].Start += offset
+		spans[i].End += offset
+	}
+
+	values, err := decodeArguments(t, params, c)
+	return values, spans, err
+}
+
+// JSONArity returns the number of parameters of the given function or method
+// type which are consumed from JSON, excluding the receiver and any context.
+func JSONArity(t reflect.Type, isMethod bool) int {
+	c := newConfig(nil)
+	c.method = isMethod
+	return c.arity(t)
+}
+
+// HasErrorResult returns true if the last result of the given function or
+// method type is an error, so that responses may be shaped before calling.
+func HasErrorResult(t reflect.Type) bool {
+	if t == nil || t.Kind() != reflect.Func || t.NumOut() == 0 {
+		return false
+	}
+	return isError(t.Out(t.NumOut() - 1))
+}
+
+// arguments implementation.
+func arguments(t reflect.Type, s string, c *config) ([]reflect.Value, error) {
+	if c.hasCodec() {
+		return codecArguments(t, s, c)
+	}
+
+	s, err := c.convert(s)
+	if err != nil {
+		return nil, err
+	}
+
+	s, _, err = c.extractParams(blankAsEmpty(s))
+	if err != nil {
+		return nil, err
+	}
+
+	s, _, err = c.objectArgument(s, c.arity(t))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.checkDepth(s); err != nil {
+		return nil, err
+	}
+
+	// fast path for functions without params
+	if isEmptyArray(s) && c.arity(t) == 0 {
+		return decodeArguments(t, nil, c)
+	}
+
+	// decode params as they're read
+	if c.streamingDecode && !c.spreadSlice && c.positionalStructs == nil && c.keywords == nil {
+		return streamArguments(t, s, c)
+	}
+
+	// the params are only needed until they're decoded
+	b := getParamsBuffer()
+	defer putParamsBuffer(b)
+
+	params, err := b.parse(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeArguments(t, params, c)
+}
+
+// streamArguments returns the arguments of a function, reading and decoding
+// one param at a time rather than parsing them all up front.
+func streamArguments(t reflect.Type, s string, c *config) ([]reflect.Value, error) {
+	if err := c.checkFunc(t); err != nil {
+		return nil, err
+	}
+
+	types := c.params(t)
+	dec := json.NewDecoder(strings.NewReader(s))
+
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, ErrInvalidJSON
+	}
+
+	// null has no params, like parseParams
+	if d, ok := tok.(json.Delim); tok != nil && (!ok || d != '[') {
+		return nil, ErrExpectedArray
+	}
+
+	var readErr error
+	n := 0
+
+	next := func(int) (json.RawMessage, bool) {
+		if tok == nil || readErr != nil || !dec.More() {
+			return nil, false
+		}
+
+		var raw json.RawMessage
+		if readErr = dec.Decode(&raw); readErr != nil {
+			return nil, false
+		}
+
+		n++
+		return raw, true
+	}
+
+	args, bindErr := c.bind(t, next)
+
+	// count the remaining params
+	for {
+		if _, ok := next(n); !ok {
+			break
+		}
+	}
+
+	if readErr != nil {
+		return nil, ErrInvalidJSON
+	}
+
+	// closing delimiter and trailing data
+	if tok != nil {
+		if _, err := dec.Token(); err != nil {
+			return nil, ErrInvalidJSON
+		}
+	}
+
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, ErrInvalidJSON
+	}
+
+	// ignore extras
+	if c.ignoreExtraArguments && n > len(types) {
+		n = len(types)
+	}
+
+	min, max := c.arityBounds(t, types, n)
+	if err := arityErr(n, min, max); err != nil {
+		return nil, err
+	}
+
+	if bindErr != nil {
+		return nil, bindErr
+	}
+
+	return args, nil
+}
+
+// parseParams parses the json array of params.
+func parseParams(s string) ([]json.RawMessage, error) {
+	var params []json.RawMessage
+
+	if err := paramsError(json.Unmarshal([]byte(s), &params)); err != nil {
+		return nil, err
+	}
+
+	return params, nil
+}
+
+// paramsError returns the error for parsing the json array of params.
+func paramsError(err error) error {
+	if _, ok := err.(*json.SyntaxError); ok {
+		return ErrInvalidJSON
+	}
+
+	if _, ok := err.(*json.UnmarshalTypeError); ok {
+		return ErrExpectedArray
+	}
+
+	return err
+}
+
+// scanParams parses the json array of params, returning the span of each.
+func scanParams(s string) ([]json.RawMessage, []Span, error) {
+	dec := json.NewDecoder(strings.NewReader(s))
+
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, nil, ErrInvalidJSON
+	}
+
+	// defer to the regular path for errors on other values
+	if d, ok := tok.(json.Delim); !ok || d != '[' {
+		_, err := parseParams(s)
+		if err == nil {
+			err = ErrInvalidJSON
+		}
+		return nil, nil, err
+	}
+
+	var params []json.RawMessage
+	var spans []Span
+
+	for dec.More() {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, nil, ErrInvalidJSON
+		}
+		end := int(dec.InputOffset())
+		params = append(params, raw)
+		spans = append(spans, Span{Start: end - len(raw), End: end})
+	}
+
+	// closing bracket
+	if _, err := dec.Token(); err != nil {
+		return nil, nil, ErrInvalidJSON
+	}
+
+	// trailing data
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, nil, ErrInvalidJSON
+	}
+
+	return params, spans, nil
+}
+
+// decodeArguments decodes params into arguments for the given function type.
+func decodeArguments(t reflect.Type, params []json.RawMessage, c *config) ([]reflect.Value, error) {
+	if err := c.checkFunc(t); err != nil {
+		return nil, err
+	}
+
+	types := c.params(t)
+	arity := len(types)
+
+	// spread trailing params into the final slice
+	if c.spreadSlice && !c.variadic(t) && arity > 0 && types[arity-1].Kind() == reflect.Slice {
+		n := arity - 1
+		if len(params) >= n && !(len(params) == arity && (isArray(params[n]) || isNull(params[n]))) {
+			params = append(params[:n:n], joinParams(params[n:]))
+		}
+	}
+
+	// fold trailing params into a final positional struct
+	if n := arity - 1; n >= 0 && c.positionalStructs[n] && !c.variadic(t) && unrollPointer(types[n]).Kind() == reflect.Struct {
+		if len(params) > n && !(len(params) == arity && (isArray(params[n]) || jsonKind(params[n]) == "object" || isNull(params[n]))) {
+			params = append(params[:n:n], joinParams(params[n:]))
+		}
+	}
+
+	// fill named params from a trailing object of keywords
+	if c.keywords != nil {
+		var err error
+		params, err = c.trailingKeywords(params)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	min, max := c.arityBounds(t, types, len(params))
+	params, err := c.checkArity(params, min, max)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.bind(t, func(i int) (json.RawMessage, bool) {
+		if i < len(params) {
+			return params[i], true
+		}
+		return nil, false
+	})
+}
+
+// convert returns s converted to JSON when an alternative syntax is used.
+func (c *config) convert(s string) (string, error) {
+	if c.syntax == nil {
+		return s, nil
+	}
+	return c.syntax(s)
+}
+
+// checkDepth returns ErrMaxDepthExceeded when s is nested too deeply.
+func (c *config) checkDepth(s string) error {
+	if c.maxDepth > 0 && !c.hasCodec() && jsonDepth(s) > c.maxDepth {
+		return ErrMaxDepthExceeded
+	}
+	return nil
+}
+
+// checkFunc returns an error if the function's signature is unsupported.
+func (c *config) checkFunc(t reflect.Type) error {
+	// ensure there's at most one context
+	if c.contexts(t) > 1 {
+		return ErrMultipleContexts
+	}
+
+	// ensure each param may be decoded from JSON
+	i := 0
+	skip := len(c.injected)
+	for p := paramOffset(c.method); p < t.NumIn(); p++ {
+		kind := t.In(p)
+
+		switch {
+		case c.isContext(kind):
+			continue
+		case skip > 0:
+			skip--
+			continue
+		case p == t.NumIn()-1 && t.IsVariadic():
+			kind = kind.Elem()
+		}
+
+		if isUnsupported(kind) {
+			return &ArgumentError{Index: i, Err: &ParameterTypeError{Kind: unrollPointer(kind).Kind(), Type: kind}}
+		}
+		i++
+	}
+
+	return nil
+}
+
+// bind returns the arguments of a function, decoding each JSON param returned
+// by next, and injecting the context and any injected values. Params which
+// next does not return are omitted optional structs or zero-filled.
+func (c *config) bind(t reflect.Type, next func(i int) (json.RawMessage, bool)) ([]reflect.Value, error) {
+	args := c.argsBuffer(t)
+
+	// process the arguments, injecting the context wherever it appears
+	i := 0
+	injected := 0
+	for p := paramOffset(c.method); p < t.NumIn(); p++ {
+		kind := t.In(p)
+
+		if v, ok, err := c.injectedArg(kind, &injected); ok {
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, v)
+			continue
+		}
+
+		// the remaining params are the elements of a variadic slice
+		if p == t.NumIn()-1 && c.variadic(t) {
+			rest, err := c.variadicArgument(i, kind, next)
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, rest)
+			continue
+		}
+
+		// omitted optional structs and zero-filled params
+		raw, ok := next(i)
+		if !ok {
+			args = append(args, reflect.Zero(kind))
+			i++
+			continue
+		}
+
+		arg := c.newArg(p, kind)
+		if err := c.paramConfig(i).decodeArgument(i, kind, raw, arg.Interface()); err != nil {
+			return nil, err
+		}
+
+		args = append(args, arg.Elem())
+		i++
+	}
+
+	// ensure every injected value has a parameter
+	if injected < len(c.injected) {
+		return nil, ErrInjectedType
+	}
+
+	return args, nil
+}
+
+// injectedArg returns the context or the next injected value, advancing
+// injected, when a parameter of type t isn't consumed from JSON.
+func (c *config) injectedArg(t reflect.Type, injected *int) (reflect.Value, bool, error) {
+	if c.isContext(t) {
+		ctx := reflect.ValueOf(c.context())
+		if !ctx.Type().AssignableTo(t) {
+			return reflect.Value{}, true, ErrContextType
+		}
+		return ctx, true, nil
+	}
+
+	if *injected < len(c.injected) {
+		v := c.injected[*injected]
+		if !v.IsValid() || !v.Type().AssignableTo(t) {
+			return reflect.Value{}, true, ErrInjectedType
+		}
+		*injected++
+		return v, true, nil
+	}
+
+	return reflect.Value{}, false, nil
+}
+
+// bindValues returns the arguments of a function from Go values in place of
+// the JSON params, injecting the context and any injected values as bind
+// does.
+func (c *config) bindValues(t reflect.Type, values []interface{}) ([]reflect.Value, error) {
+	if err := c.checkFunc(t); err != nil {
+		return nil, err
+	}
+
+	min, max := c.arityBounds(t, c.params(t), len(values))
+	if err := arityErr(len(values), min, max); err != nil {
+		return nil, err
+	}
+
+	var args []reflect.Value
+	i := 0
+	injected := 0
+	for p := paramOffset(c.method); p < t.NumIn(); p++ {
+		kind := t.In(p)
+
+		if v, ok, err := c.injectedArg(kind, &injected); ok {
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, v)
+			continue
+		}
+
+		// the remaining values are the elements of a variadic slice
+		if p == t.NumIn()-1 && c.variadic(t) {
+			rest := reflect.Zero(kind)
+			for ; i < len(values); i++ {
+				v, err := valueArgument(i, kind.Elem(), values[i])
+				if err != nil {
+					return nil, err
+				}
+				rest = reflect.Append(rest, v)
+			}
+			args = append(args, rest)
+			continue
+		}
+
+		// omitted optional structs and zero-filled params
+		if i >= len(values) {
+			args = append(args, reflect.Zero(kind))
+			i++
+			continue
+		}
+
+		v, err := valueArgument(i, kind, values[i])
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, v)
+		i++
+	}
+
+	// ensure every injected value has a parameter
+	if injected < len(c.injected) {
+		return nil, ErrInjectedType
+	}
+
+	return args, nil
+}
+
+// valueArgument returns the value at index i as an argument of type t, or an
+// *ArgumentError when it is not assignable. Nil is the zero value of nilable
+// types.
+func valueArgument(i int, t reflect.Type, value interface{}) (reflect.Value, error) {
+	if value == nil {
+		if isNilable(t) {
+			return reflect.Zero(t), nil
+		}
+		return reflect.Value{}, &ArgumentError{Index: i, Err: &ArgumentTypeError{Expected: t}}
+	}
+
+	v := reflect.ValueOf(value)
+	if !v.Type().AssignableTo(t) {
+		return reflect.Value{}, &ArgumentError{Index: i, Err: &ArgumentTypeError{Type: v.Type(), Expected: t}}
+	}
+
+	return v, nil
+}
+
+// variadicArgument returns a slice of type t holding the params returned by
+// next from index i onwards, each decoded as an element. The slice is nil
+// when there are none, as in a Go call which passes no variadic arguments.
+func (c *config) variadicArgument(i int, t reflect.Type, next func(i int) (json.RawMessage, bool)) (reflect.Value, error) {
+	rest := reflect.Zero(t)
+	for ; ; i++ {
+		raw, ok := next(i)
+		if !ok {
+			return rest, nil
+		}
+
+		arg := reflect.New(t.Elem())
+		if err := c.paramConfig(i).decodeArgument(i, t.Elem(), raw, arg.Interface()); err != nil {
+			return reflect.Value{}, err
+		}
+
+		rest = reflect.Append(rest, arg.Elem())
+	}
+}
+
+// contextErr returns the error of the context argument, if any.
+func contextErr(t reflect.Type, args []reflect.Value, c *config) error {
+	if ctx := injectedContext(t, 0, args, c); ctx != nil {
+		return ctx.Err()
+	}
+	return nil
+}
+
+// injectedContext returns the context among args, whose types are the
+// parameters of t starting at offset, or nil if there is none.
+func injectedContext(t reflect.Type, offset int, args []reflect.Value, c *config) context.Context {
+	for i, a := range args {
+		p := offset + i
+		if p >= t.NumIn() || !c.isContext(t.In(p)) {
+			continue
+		}
+
+		if ctx, ok := a.Interface().(context.Context); ok {
+			return ctx
+		}
+	}
+	return nil
+}
+
+// checkArity returns the params, or an error when the number of params is
+// not between min and arity.
+func (c *config) checkArity(params []json.RawMessage, min, arity int) ([]json.RawMessage, error) {
+	// ignore extras
+	if c.ignoreExtraArguments && len(params) > arity {
+		params = params[:arity]
+	}
+
+	if err := arityErr(len(params), min, arity); err != nil {
+		return nil, err
+	}
+
+	return params, nil
+}
+
+// arityErr returns an *ArityError when n is not between min and arity.
+func arityErr(n, min, arity int) error {
+	if n >= min && n <= arity {
+		return nil
+	}
+
+	// too few or too many
+	e := &ArityError{Expected: arity, Got: n}
+	if n < min {
+		e.Expected = min
+	}
+
+	for i := min; i <= arity && min < arity; i++ {
+		e.Accepted = append(e.Accepted, i)
+	}
+
+	return e
+}
+
+// variadic returns true if t is variadic and its final slice parameter is
+// consumed from JSON.
+func (c *config) variadic(t reflect.Type) bool {
+	return t.IsVariadic() && c.arity(t) > 0
+}
+
+// arityBounds returns the minimum and maximum number of params of t when n
+// are given, the trailing params of variadic functions being unbounded.
+func (c *config) arityBounds(t reflect.Type, types []reflect.Type, n int) (int, int) {
+	if !c.variadic(t) {
+		return c.minArity(types), len(types)
+	}
+
+	min := c.minArity(types[:len(types)-1])
+	if n < min {
+		return min, min
+	}
+	return min, n
+}
+
+// minArity returns the number of leading params which must be given, those
+// after it being optional structs, or zero when zero-filling.
+func (c *config) minArity(types []reflect.Type) int {
+	if c.zeroFill {
+		return 0
+	}
+
+	n := len(types)
+	for n > 0 && c.optionalStructs[n-1] && unrollPointer(types[n-1]).Kind() == reflect.Struct {
+		n--
+	}
+	return n
+}
+
+// decodeValue returns the raw param at index i decoded as t.
+func (c *config) decodeValue(i int, t reflect.Type, raw json.RawMessage) (reflect.Value, error) {
+	v := reflect.New(t)
+	if err := c.decodeArgument(i, t, raw, v.Interface()); err != nil {
+		return reflect.Value{}, err
+	}
+	return v.Elem(), nil
+}
+
+// decodeArgument decodes the raw param at index i into value, a pointer to t.
+func (c *config) decodeArgument(i int, t reflect.Type, raw json.RawMessage, value interface{}) error {
+	for _, fn := range c.preDecoders {
+		if err := fn(i, raw); err != nil {
+			return &ArgumentError{Index: i, Err: err}
+		}
+	}
+
+	if c.hasCodec() {
+		return c.codecDecode(i, raw, value)
+	}
+
+	if c.rejectNull && !isNilable(t) && isNull(raw) {
+		return &ArgumentError{Index: i, Err: ErrNullNotAllowed}
+	}
+
+	if c.nonNilPointers && t.Kind() == reflect.Ptr && isNull(raw) {
+		return &ArgumentError{Index: i, Err: ErrNullPointer}
+	}
+
+	// checked before rewriting, which may collapse duplicates
+	if c.rejectDuplicateKeys && hasObjects(t) {
+		if err := checkDuplicateKeys(raw, t); err != nil {
+			if _, ok := err.(*DuplicateKeyError); ok {
+				return &ArgumentError{Index: i, Err: err}
+			}
+		}
+	}
+
+	if c.positionalStructs[i] && unrollPointer(t).Kind() == reflect.Struct && isArray(raw) {
+		return c.positionalStruct(i, t, raw, value)
+	}
+
+	if c.coerceScalarToSlice && isCoercibleSlice(t) && !isArray(raw) && !isNull(raw) {
+		raw = joinParams([]json.RawMessage{raw})
+	}
+
+	if c.scalarMapValuesToSlice && isSliceMap(t) {
+		raw = wrapScalarMapValues(raw)
+	}
+
+	if c.keyTransform != nil && isStructLike(t) {
+		if b, err := transformStructKeys(raw, t, c.keyTransform); err == nil {
+			raw = b
+		}
+	}
+
+	if c.requiredFields && unrollPointer(t).Kind() == reflect.Struct && !isNull(raw) {
+		if err := checkRequired(unrollPointer(t), raw); err != nil {
+			return &ArgumentError{Index: i, Err: err}
+		}
+	}
+
+	if c.strictArrayLength && unrollPointer(t).Kind() == reflect.Array {
+		var elems []json.RawMessage
+		if json.Unmarshal(raw, &elems) == nil && elems != nil && len(elems) != unrollPointer(t).Len() {
+			return &ArgumentError{Index: i, Err: &ArrayLengthError{Expected: unrollPointer(t).Len(), Got: len(elems)}}
+		}
+	}
+
+	// non-finite floats have no JSON representation
+	if c.allowNonFiniteFloats && isFloat(t) {
+		if f, ok := nonFiniteFloat(raw); ok {
+			allocate(reflect.ValueOf(value).Elem()).SetFloat(f)
+			return c.validate(i, value)
+		}
+	}
+
+	// booleans may be given as 0 or 1
+	if c.coerceBool && unrollPointer(t).Kind() == reflect.Bool && jsonKind(raw) == "number" {
+		switch string(bytes.TrimSpace(raw)) {
+		case "0":
+			raw = json.RawMessage("false")
+		case "1":
+			raw = json.RawMessage("true")
+		default:
+			return unmarshalError(i, UnmarshalError{Value: "number", Type: t})
+		}
+	}
+
+	// durations may be given as strings such as "5s"
+	if unrollPointer(t) == durationType && jsonKind(raw) == "string" {
+		var str string
+		if err := json.Unmarshal(raw, &str); err != nil {
+			return err
+		}
+
+		d, err := time.ParseDuration(str)
+		if err != nil {
+			return &ArgumentError{Index: i, Err: err}
+		}
+
+		allocate(reflect.ValueOf(value).Elem()).SetInt(int64(d))
+		return c.validate(i, value)
+	}
+
+	// urls are given as strings
+	if unrollPointer(t) == urlType && !isNull(raw) {
+		if jsonKind(raw) != "string" {
+			return unmarshalError(i, UnmarshalError{Value: jsonKind(raw), Type: t})
+		}
+
+		var str string
+		if err := json.Unmarshal(raw, &str); err != nil {
+			return err
+		}
+
+		u, err := url.Parse(str)
+		if err != nil {
+			return &ArgumentError{Index: i, Err: err}
+		}
+
+		allocate(reflect.ValueOf(value).Elem()).Set(reflect.ValueOf(*u))
+		return c.validate(i, value)
+	}
+
+	if c.strictIntegers && isInteger(t) && jsonKind(raw) == "number" {
+		lit, err := integerLiteral(raw)
+		if err != nil {
+			return &ArgumentError{Index: i, Err: err}
+		}
+		raw = lit
+	}
+
+	input := raw
+	if isBigNumber(t) {
+		input = bigNumberLiteral(t, raw)
+	}
+
+	err := c.decode(input, value)
+
+	// big numbers report parse errors of their own
+	if err != nil && isBigNumber(t) {
+		return unmarshalError(i, UnmarshalError{Value: jsonKind(raw), Type: t})
+	}
+
+	// locate the failing element
+	if err != nil && c.elementErrors && isElementType(t) {
+		if e := c.elementError(i, t, input); e != nil {
+			return e
+		}
+	}
+
+	// text unmarshalers report parse errors such as an invalid address
+	if _, ok := err.(*json.UnmarshalTypeError); err != nil && !ok && isTextUnmarshaler(t) && !isUnmarshaler(t) {
+		return &ArgumentError{Index: i, Err: err}
+	}
+
+	// custom unmarshalers report their own errors, and sized integers at any
+	// depth report values out of range
+	if e, ok := err.(*json.UnmarshalTypeError); ok && !isUnmarshaler(t) {
+		return &ArgumentError{Index: i, Err: typeError(e)}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return c.validate(i, value)
+}
+
+// elementError returns an *ElementError for the first element of a slice or
+// array argument which fails to decode, or nil.
+func (c *config) elementError(i int, t reflect.Type, raw json.RawMessage) error {
+	var elems []json.RawMessage
+	if json.Unmarshal(raw, &elems) != nil {
+		return nil
+	}
+
+	et := unrollPointer(t).Elem()
+	for j, elem := range elems {
+		err := c.decode(elem, reflect.New(et).Interface())
+
+		if e, ok := err.(*json.UnmarshalTypeError); ok && !isUnmarshaler(et) {
+			err = typeError(e)
+		}
+
+		if err != nil {
+			return &ElementError{Index: i, Element: j, Err: err}
+		}
+	}
+
+	return nil
+}
+
+// positionalStruct decodes a JSON array into the exported fields of the
+// struct pointed to by value, in declaration order.
+func (c *config) positionalStruct(i int, t reflect.Type, raw json.RawMessage, value interface{}) error {
+	var elems []json.RawMessage
+	if err := json.Unmarshal(raw, &elems); err != nil {
+		return err
+	}
+
+	fields := positionalFields(unrollPointer(t))
+	if len(elems) != len(fields) {
+		return &ArgumentError{Index: i, Err: &ArrayLengthError{Expected: len(fields), Got: len(elems)}}
+	}
+
+	s := allocate(reflect.ValueOf(value).Elem())
+	for j, f := range fields {
+		field := s.Field(f)
+		err := c.decode(elems[j], field.Addr().Interface())
+
+		if e, ok := err.(*json.UnmarshalTypeError); ok && !isUnmarshaler(field.Type()) {
+			err = typeError(e)
+		}
+
+		if err != nil {
+			return &ElementError{Index: i, Element: j, Err: err}
+		}
+	}
+
+	return c.validate(i, value)
+}
+
+// validate runs the validators against a decoded argument.
+func (c *config) validate(i int, value interface{}) error {
+	for _, validate := range c.validators {
+		if err := validate(i, reflect.ValueOf(value).Elem()); err != nil {
+			return &ArgumentError{Index: i, Err: err}
+		}
+	}
+
+	return nil
 }
@@ -2,15 +2,65 @@ package jsoncall_test
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"math"
+	"math/big"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+	"unsafe"
 
 	"github.com/tj/assert"
 	jsoncall "github.com/tj/go-jsoncall"
 )
 
+// validationErrors is an aggregate error.
+type validationErrors struct {
+	errs []error
+}
+
+func (e *validationErrors) Error() string {
+	return fmt.Sprintf("%d validation errors", len(e.errs))
+}
+
+func (e *validationErrors) Unwrap() []error {
+	return e.errs
+}
+
+// codeError is a concrete error type implemented by pointers.
+type codeError struct {
+	Code int
+}
+
+func (e *codeError) Error() string {
+	return fmt.Sprintf("error code %d", e.Code)
+}
+
+// statusError is a concrete error type implemented by values, the zero value
+// meaning success.
+type statusError string
+
+func (e statusError) Error() string {
+	return string(e)
+}
+
+// errorList is an aggregate of errors which is not itself an error.
+type errorList []error
+
+func (e errorList) Unwrap() []error {
+	return e
+}
+
 func abs(v float64) float64 {
 	return math.Abs(v)
 }
@@ -47,10 +97,125 @@ func addUsers(u []User) error {
 	return nil
 }
 
+func addUserPointers(u []*User) error {
+	return nil
+}
+
+func addUserDoublePointer(u **User) error {
+	return nil
+}
+
 func addUserContext(ctx context.Context, u User) error {
 	return nil
 }
 
+func addTags(tags map[string][]string) error {
+	return nil
+}
+
+type RequestContext interface {
+	context.Context
+	RequestID() string
+}
+
+type requestContext struct {
+	context.Context
+	id string
+}
+
+func (r requestContext) RequestID() string {
+	return r.id
+}
+
+type Logger interface {
+	Log(string)
+}
+
+type contextLogger struct {
+	context.Context
+}
+
+func (contextLogger) Log(string) {}
+
+func addUserRequestContext(ctx RequestContext, u User) error {
+	return nil
+}
+
+func addUserLogger(log Logger, u User) error {
+	return nil
+}
+
+type Species string
+
+const (
+	Ferret Species = "ferret"
+	Cat    Species = "cat"
+)
+
+func addPetSpecies(name string, s Species) error {
+	return nil
+}
+
+// NullString is a sql.NullString decoded from a JSON string or null.
+type NullString struct {
+	sql.NullString
+}
+
+func (n *NullString) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		n.String, n.Valid = "", false
+		return nil
+	}
+
+	if err := json.Unmarshal(b, &n.String); err != nil {
+		return fmt.Errorf("expected string or null: %w", err)
+	}
+
+	n.Valid = true
+	return nil
+}
+
+func setNickname(name NullString) error {
+	return nil
+}
+
+// PetError is a custom error type.
+type PetError struct {
+	Name string
+}
+
+func (e *PetError) Error() string {
+	return "invalid pet " + e.Name
+}
+
+var errPetExists = errors.New("pet exists")
+
+type Account struct {
+	UserName string
+	Settings struct {
+		DarkMode bool
+	}
+}
+
+func addAccount(a Account) error {
+	return nil
+}
+
+func addAccounts(a []*Account) error {
+	return nil
+}
+
+// camelCase converts snake_case to CamelCase.
+func camelCase(s string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(s, "_") {
+		if part != "" {
+			b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+	}
+	return b.String()
+}
+
 func addPet(name string) error {
 	return errors.New("error adding pet")
 }
@@ -61,6 +226,26 @@ func (m *mathService) Sum(ctx context.Context, nums []int) int {
 	return sum(nums...)
 }
 
+func maxOf[T int | float64 | string](a, b T) T {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func first[T any](ctx context.Context, v []T) T {
+	return v[0]
+}
+
+type stack[T any] struct {
+	items []T
+}
+
+func (s *stack[T]) Push(ctx context.Context, v T) int {
+	s.items = append(s.items, v)
+	return len(s.items)
+}
+
 // Test normalization of arguments.
 func TestNormalize(t *testing.T) {
 	assert.Equal(t, `[]`, jsoncall.Normalize(``))
@@ -71,6 +256,117 @@ func TestNormalize(t *testing.T) {
 	assert.Equal(t, `["Hello"]`, jsoncall.Normalize(`  "Hello"  `))
 	assert.Equal(t, `[{ "name": "Tobi" }]`, jsoncall.Normalize(`{ "name": "Tobi" }`))
 	assert.Equal(t, `[1, 2, 3]`, jsoncall.Normalize(`[1, 2, 3]`))
+	assert.Equal(t, `[1,2]xyz`, jsoncall.Normalize(`[1,2]xyz`))
+}
+
+// Test strict normalization of arguments.
+func TestNormalizeStrict(t *testing.T) {
+	t.Run("should normalize valid input", func(t *testing.T) {
+		cases := map[string]string{
+			``:                   `[]`,
+			`  [1, 2]  `:         `[1, 2]`,
+			`5`:                  `[5]`,
+			`{ "name": "Tobi" }`: `[{ "name": "Tobi" }]`,
+		}
+
+		for input, output := range cases {
+			s, err := jsoncall.NormalizeStrict(input)
+			assert.NoError(t, err, input)
+			assert.Equal(t, output, s)
+		}
+	})
+
+	t.Run("should error on trailing content", func(t *testing.T) {
+		cases := []string{
+			`[1,2]xyz`,
+			`[1,2][3]`,
+			`{"a":1}{"b":2}`,
+			`5 6`,
+			`[1,`,
+		}
+
+		for _, input := range cases {
+			_, err := jsoncall.NormalizeStrict(input)
+			assert.Equal(t, jsoncall.ErrInvalidJSON, err, input)
+		}
+	})
+}
+
+// Test normalization of command-line arguments.
+func TestNormalizeArgs(t *testing.T) {
+	assert.Equal(t, `[]`, jsoncall.NormalizeArgs(nil))
+	assert.Equal(t, `[1,2]`, jsoncall.NormalizeArgs([]string{"1", "2"}))
+	assert.Equal(t, `[5,"hello",true,null]`, jsoncall.NormalizeArgs([]string{"5", "hello", "true", "null"}))
+	assert.Equal(t, `["5","hello world"]`, jsoncall.NormalizeArgs([]string{`"5"`, "hello world"}))
+	assert.Equal(t, `[{ "name": "Tobi" },[1, 2]]`, jsoncall.NormalizeArgs([]string{`{ "name": "Tobi" }`, `[1, 2]`}))
+	assert.Equal(t, `["{ name }","[1,"]`, jsoncall.NormalizeArgs([]string{`{ name }`, `[1,`}))
+
+	v, err := jsoncall.CallFunc(add, jsoncall.NormalizeArgs([]string{"1", "2"}))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, v[0].Interface())
+}
+
+// Test blank input across entry points.
+func TestBlankInput(t *testing.T) {
+	noop := func() {}
+	inputs := []string{``, `  `, "\n\t"}
+
+	t.Run("should call functions without params", func(t *testing.T) {
+		for _, args := range inputs {
+			_, err := jsoncall.CallFunc(noop, args)
+			assert.NoError(t, err, args)
+
+			_, err = jsoncall.CallFunc(func(ctx context.Context) {}, args, jsoncall.WithStreamingDecode())
+			assert.NoError(t, err, args)
+
+			_, _, err = jsoncall.ArgumentsOfFuncMeta(reflect.TypeOf(noop), args)
+			assert.NoError(t, err, args)
+
+			v, err := jsoncall.Call0(func() int { return 1 }, args)
+			assert.NoError(t, err, args)
+			assert.Equal(t, 1, v)
+
+			n, err := jsoncall.CountParams(args)
+			assert.NoError(t, err, args)
+			assert.Equal(t, 0, n)
+		}
+	})
+
+	t.Run("should call methods without params", func(t *testing.T) {
+		s := &petService{}
+		m, _ := reflect.TypeOf(s).MethodByName("Count")
+
+		for _, args := range inputs {
+			v, err := jsoncall.CallMethod(s, m, args)
+			assert.NoError(t, err, args)
+			assert.Equal(t, 0, v[0].Interface())
+		}
+	})
+
+	t.Run("should treat blank input as no arguments", func(t *testing.T) {
+		for _, args := range inputs {
+			_, err := jsoncall.CallFunc(add, args)
+			assert.EqualError(t, err, `Too few arguments: expected 2, got 0`, args)
+
+			_, err = jsoncall.CallFunc(add, args, jsoncall.WithStreamingDecode())
+			assert.EqualError(t, err, `Too few arguments: expected 2, got 0`, args)
+
+			_, _, err = jsoncall.ArgumentsOfFuncMeta(reflect.TypeOf(add), args)
+			assert.EqualError(t, err, `Too few arguments: expected 2, got 0`, args)
+
+			_, err = jsoncall.Call2(add, args)
+			assert.EqualError(t, err, `Too few arguments: expected 2, got 0`, args)
+		}
+	})
+
+	t.Run("should still require arrays for other input", func(t *testing.T) {
+		_, err := jsoncall.CallFunc(abs, `5`)
+		assert.Equal(t, jsoncall.ErrExpectedArray, err)
+
+		v, err := jsoncall.CallFunc(abs, jsoncall.Normalize(`-5`))
+		assert.NoError(t, err)
+		assert.Equal(t, 5.0, v[0].Interface())
+	})
 }
 
 // Test arguments from a function signature.
@@ -82,19 +378,101 @@ func TestArgumentsOfFunc(t *testing.T) {
 		assert.Len(t, vals, 0)
 	})
 
+	t.Run("should support empty input for no params", func(t *testing.T) {
+		noop := func() {}
+		for _, s := range []string{``, ` `, `[]`, ` [ ] `} {
+			vals, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(noop), s)
+			assert.NoError(t, err)
+			assert.Len(t, vals, 0)
+		}
+
+		vals, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(func(context.Context) {}), `[]`)
+		assert.NoError(t, err)
+		assert.Len(t, vals, 1)
+	})
+
+	t.Run("should error when arguments are passed for no params", func(t *testing.T) {
+		noop := func() {}
+		_, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(noop), `[1]`)
+		assert.EqualError(t, err, `Too many arguments: expected 0, got 1`)
+	})
+
 	t.Run("should error when the input is invalid json", func(t *testing.T) {
 		_, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(add), `[5, hey]`)
 		assert.EqualError(t, err, `Invalid JSON`)
 	})
 
+	t.Run("should error when the input is not an array", func(t *testing.T) {
+		for _, s := range []string{`{"a": 1}`, `"hello"`, `5`, `true`} {
+			_, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(add), s)
+			assert.Equal(t, jsoncall.ErrExpectedArray, err, s)
+			assert.EqualError(t, err, `Arguments must be a JSON array`)
+		}
+
+		_, _, err := jsoncall.ArgumentsOfFuncMeta(reflect.TypeOf(add), `{"a": 1}`)
+		assert.Equal(t, jsoncall.ErrExpectedArray, err)
+	})
+
 	t.Run("should error when too few arguments are passed", func(t *testing.T) {
 		_, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(add), `[1]`)
-		assert.EqualError(t, err, `Too few arguments passed`)
+		assert.EqualError(t, err, `Too few arguments: expected 2, got 1`)
+		assert.True(t, errors.Is(err, jsoncall.ErrTooFewArguments))
+		assert.False(t, errors.Is(err, jsoncall.ErrTooManyArguments))
+
+		var e *jsoncall.ArityError
+		assert.True(t, errors.As(err, &e))
+		assert.Equal(t, 2, e.Expected)
+		assert.Equal(t, 1, e.Got)
+		assert.Equal(t, 400, jsoncall.ErrorCode(err))
 	})
 
 	t.Run("should error when too many arguments are passed", func(t *testing.T) {
 		_, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(add), `[1, 2, 3]`)
-		assert.EqualError(t, err, `Too many arguments passed`)
+		assert.EqualError(t, err, `Too many arguments: expected 2, got 3`)
+		assert.True(t, errors.Is(err, jsoncall.ErrTooManyArguments))
+		assert.False(t, errors.Is(err, jsoncall.ErrTooFewArguments))
+	})
+
+	t.Run("should ignore extra arguments via WithIgnoreExtraArguments", func(t *testing.T) {
+		vals, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(add), `[1, 2, 3, "four"]`, jsoncall.WithIgnoreExtraArguments())
+		assert.NoError(t, err)
+		assert.Len(t, vals, 2)
+		assert.Equal(t, 2, vals[1].Interface())
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(add), `[1]`, jsoncall.WithIgnoreExtraArguments())
+		assert.EqualError(t, err, `Too few arguments: expected 2, got 1`)
+	})
+
+	t.Run("should zero-fill missing trailing arguments via WithZeroFill", func(t *testing.T) {
+		fn := func(ctx context.Context, name string, user User, limit *int) {}
+
+		vals, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `["Tobi"]`, jsoncall.WithZeroFill())
+		assert.NoError(t, err)
+		assert.Len(t, vals, 4)
+		assert.Equal(t, "Tobi", vals[1].Interface())
+		assert.Equal(t, User{}, vals[2].Interface())
+		assert.Nil(t, vals[3].Interface())
+
+		vals, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `["Tobi", { "name": "Loki" }, 5]`, jsoncall.WithZeroFill())
+		assert.NoError(t, err)
+		assert.Equal(t, User{Name: "Loki"}, vals[2].Interface())
+		assert.Equal(t, 5, *vals[3].Interface().(*int))
+
+		vals, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[]`, jsoncall.WithZeroFill(), jsoncall.WithStreamingDecode())
+		assert.NoError(t, err)
+		assert.Equal(t, "", vals[1].Interface())
+		assert.Equal(t, User{}, vals[2].Interface())
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `["Tobi", {}, 5, 6]`, jsoncall.WithZeroFill())
+		assert.EqualError(t, err, `Too many arguments: expected 0, 1, 2 or 3, got 4`)
+
+		vals, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `["Tobi", {}, 5, 6]`, jsoncall.WithZeroFill(), jsoncall.WithIgnoreExtraArguments())
+		assert.NoError(t, err)
+		assert.Equal(t, 5, *vals[3].Interface().(*int))
+
+		v, err := jsoncall.CallFunc(add, `[1]`, jsoncall.WithZeroFill())
+		assert.NoError(t, err)
+		assert.Equal(t, 1, v[0].Interface())
 	})
 
 	t.Run("should error when arguments are incorrect types", func(t *testing.T) {
@@ -134,6 +512,144 @@ func TestArgumentsOfFunc(t *testing.T) {
 		assert.Empty(t, vals[0].Interface())
 	})
 
+	t.Run("should support pointers to primitives", func(t *testing.T) {
+		fn := func(n *int, s *string, b *bool) {}
+
+		vals, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[5, "Tobi", false]`)
+		assert.NoError(t, err)
+		assert.Equal(t, 5, *vals[0].Interface().(*int))
+		assert.Equal(t, "Tobi", *vals[1].Interface().(*string))
+		assert.False(t, *vals[2].Interface().(*bool))
+
+		vals, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[null, null, null]`)
+		assert.NoError(t, err)
+		assert.Nil(t, vals[0].Interface().(*int))
+		assert.Nil(t, vals[1].Interface().(*string))
+		assert.Nil(t, vals[2].Interface().(*bool))
+	})
+
+	t.Run("should name the pointed-to type of primitives in errors", func(t *testing.T) {
+		fn := func(n *int, s *string, b *bool, nums *[]int) {}
+
+		cases := []struct {
+			args string
+			err  string
+		}{
+			{`["5", null, null, null]`, `Incorrect type string, expected number`},
+			{`[null, 5, null, null]`, `Incorrect type number, expected string`},
+			{`[null, null, "yes", null]`, `Incorrect type string, expected boolean`},
+			{`[null, null, null, 5]`, `Incorrect type number, expected array of numbers`},
+		}
+
+		for _, c := range cases {
+			_, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), c.args)
+			assert.EqualError(t, err, c.err, c.args)
+		}
+	})
+
+	t.Run("should call functions with pointers to primitives", func(t *testing.T) {
+		fn := func(n *int) int {
+			if n == nil {
+				return -1
+			}
+			return *n
+		}
+
+		v, err := jsoncall.CallFunc(fn, `[5]`)
+		assert.NoError(t, err)
+		assert.Equal(t, 5, v[0].Interface())
+
+		v, err = jsoncall.CallFunc(fn, `[null]`)
+		assert.NoError(t, err)
+		assert.Equal(t, -1, v[0].Interface())
+	})
+
+	t.Run("should support null for structs by default", func(t *testing.T) {
+		vals, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(addUser), `[null]`)
+		assert.NoError(t, err)
+		assert.Equal(t, User{}, vals[0].Interface())
+	})
+
+	t.Run("should reject null for non-pointers via WithRejectNull", func(t *testing.T) {
+		_, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(addUser), `[null]`, jsoncall.WithRejectNull())
+		assert.EqualError(t, err, `Argument 0: null not allowed for non-pointer parameter`)
+		assert.True(t, errors.Is(err, jsoncall.ErrNullNotAllowed))
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(add), `[1, null]`, jsoncall.WithRejectNull())
+		assert.EqualError(t, err, `Argument 1: null not allowed for non-pointer parameter`)
+	})
+
+	t.Run("should allow null for nilable types via WithRejectNull", func(t *testing.T) {
+		_, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(addUserPointer), `[null]`, jsoncall.WithRejectNull())
+		assert.NoError(t, err)
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(addUsers), `[null]`, jsoncall.WithRejectNull())
+		assert.NoError(t, err)
+	})
+
+	t.Run("should treat objects as the single argument via WithObjectAsSingleArg", func(t *testing.T) {
+		single := jsoncall.WithObjectAsSingleArg()
+
+		vals, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(addUserContext), `{ "name": "Tobi" }`, single)
+		assert.NoError(t, err)
+		assert.Equal(t, User{Name: "Tobi"}, vals[1].Interface())
+
+		fn := func(m map[string]interface{}) {}
+		vals, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), ` {"a": 1}`, single)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"a": 1.0}, vals[0].Interface())
+
+		vals, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[{"a": 1}]`, single)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"a": 1.0}, vals[0].Interface())
+
+		u, err := jsoncall.Call1(func(u User) string { return u.Name }, `{ "name": "Loki" }`, single)
+		assert.NoError(t, err)
+		assert.Equal(t, "Loki", u)
+
+		args := `{ "name": 5 }`
+		_, spans, err := jsoncall.ArgumentsOfFuncMeta(reflect.TypeOf(addUser), args, single)
+		assert.EqualError(t, err, `Incorrect type number, expected string`)
+		assert.Equal(t, args, args[spans[0].Start:spans[0].End])
+	})
+
+	t.Run("should error on objects for other arities via WithObjectAsSingleArg", func(t *testing.T) {
+		single := jsoncall.WithObjectAsSingleArg()
+
+		_, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(add), `{ "a": 1, "b": 2 }`, single)
+		assert.Equal(t, jsoncall.ErrObjectArgument, err)
+		assert.Equal(t, "object_argument", jsoncall.ErrorCategory(err))
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(func() {}), `{}`, single)
+		assert.Equal(t, jsoncall.ErrObjectArgument, err)
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(addUser), `{ "name": "Tobi" }`)
+		assert.Equal(t, jsoncall.ErrExpectedArray, err)
+	})
+
+	t.Run("should reject null for pointers via WithNonNilPointers", func(t *testing.T) {
+		_, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(addUserPointer), `[null]`, jsoncall.WithNonNilPointers())
+		assert.EqualError(t, err, `Argument 0: required, got null`)
+		assert.True(t, errors.Is(err, jsoncall.ErrNullPointer))
+		assert.Equal(t, 400, jsoncall.ErrorCode(err))
+
+		vals, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(addUserPointer), `[{ "name": "Tobi" }]`, jsoncall.WithNonNilPointers())
+		assert.NoError(t, err)
+		assert.Equal(t, "Tobi", vals[0].Interface().(*User).Name)
+
+		fn := func(ctx context.Context, ids []int, n *int) {}
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[null, null]`, jsoncall.WithNonNilPointers())
+		assert.EqualError(t, err, `Argument 1: required, got null`)
+	})
+
+	t.Run("should allow null for non-pointers via WithNonNilPointers", func(t *testing.T) {
+		_, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(addUsers), `[null]`, jsoncall.WithNonNilPointers())
+		assert.NoError(t, err)
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(addUserPointers), `[[null]]`, jsoncall.WithNonNilPointers())
+		assert.NoError(t, err)
+	})
+
 	t.Run("should support context as the first argument", func(t *testing.T) {
 		vals, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(addUserContext), `[{ "name": "Tobi" }]`)
 		assert.NoError(t, err)
@@ -142,6 +658,31 @@ func TestArgumentsOfFunc(t *testing.T) {
 		assert.Equal(t, "Tobi", vals[1].Interface().(User).Name)
 	})
 
+	t.Run("should support context at any position", func(t *testing.T) {
+		fn := func(id int, ctx context.Context, name string) {}
+		vals, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[1, "Tobi"]`)
+		assert.NoError(t, err)
+		assert.Len(t, vals, 3)
+		assert.Equal(t, 1, vals[0].Interface())
+		assert.Implements(t, (*context.Context)(nil), vals[1].Interface(), "should have a context")
+		assert.Equal(t, "Tobi", vals[2].Interface())
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[1, 2]`)
+		assert.EqualError(t, err, `Incorrect type number, expected string`)
+
+		last := func(name string, ctx context.Context) {}
+		vals, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(last), `["Tobi"]`)
+		assert.NoError(t, err)
+		assert.Len(t, vals, 2)
+		assert.Implements(t, (*context.Context)(nil), vals[1].Interface(), "should have a context")
+	})
+
+	t.Run("should error on multiple contexts", func(t *testing.T) {
+		fn := func(a context.Context, name string, b context.Context) {}
+		_, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `["Tobi"]`)
+		assert.Equal(t, jsoncall.ErrMultipleContexts, err)
+	})
+
 	t.Run("should support custom contexts via WithContextFunc", func(t *testing.T) {
 		var called bool
 		_, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(addUserContext), `[{ "name": "Tobi" }]`, jsoncall.WithContextFunc(func() context.Context {
@@ -152,80 +693,2273 @@ func TestArgumentsOfFunc(t *testing.T) {
 		assert.True(t, called, "should call the function")
 	})
 
-	t.Run("should support slices of structs", func(t *testing.T) {
-		vals, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(addUsers), `[[{ "name": "Tobi" }, { "name": "Loki" }]]`)
+	t.Run("should support custom contexts via WithContext", func(t *testing.T) {
+		type key struct{}
+		ctx := context.WithValue(context.Background(), key{}, "value")
+		vals, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(addUserContext), `[{ "name": "Tobi" }]`, jsoncall.WithContext(ctx))
 		assert.NoError(t, err)
-		assert.Len(t, vals, 1)
-		assert.Equal(t, "Tobi", vals[0].Interface().([]User)[0].Name)
+		assert.Equal(t, "value", vals[0].Interface().(context.Context).Value(key{}))
 	})
 
-	t.Run("should error on variadic functions", func(t *testing.T) {
-		// TODO: support variadic functions
-		_, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(sum), `[1, 2, 3, 4]`)
-		assert.EqualError(t, err, `Variadic functions are not yet supported`)
+	t.Run("should use the last of WithContext and WithContextFunc", func(t *testing.T) {
+		type key struct{}
+		a := context.WithValue(context.Background(), key{}, "a")
+		b := context.WithValue(context.Background(), key{}, "b")
+
+		vals, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(addUserContext), `[{}]`, jsoncall.WithContext(a), jsoncall.WithContextFunc(func() context.Context {
+			return b
+		}))
+		assert.NoError(t, err)
+		assert.Equal(t, "b", vals[0].Interface().(context.Context).Value(key{}))
+
+		vals, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(addUserContext), `[{}]`, jsoncall.WithContextFunc(func() context.Context {
+			return b
+		}), jsoncall.WithContext(a))
+		assert.NoError(t, err)
+		assert.Equal(t, "a", vals[0].Interface().(context.Context).Value(key{}))
 	})
-}
 
-// Test arguments from a method signature.
-func TestArgumentsOfMethod(t *testing.T) {
-	s := &mathService{}
+	t.Run("should fall back to a background context when the factory returns nil", func(t *testing.T) {
+		nilContext := jsoncall.WithContextFunc(func() context.Context { return nil })
 
-	m, ok := reflect.TypeOf(s).MethodByName("Sum")
-	assert.True(t, ok)
+		vals, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(addUserContext), `[{ "name": "Tobi" }]`, nilContext)
+		assert.NoError(t, err)
+		assert.Equal(t, context.Background(), vals[0].Interface())
 
-	vals, err := jsoncall.ArgumentsOfMethod(m, `[[1,2,3,4]]`)
-	assert.NoError(t, err)
-	assert.Implements(t, (*context.Context)(nil), vals[0].Interface(), "should have a context")
-	assert.Equal(t, []int{1, 2, 3, 4}, vals[1].Interface())
-}
+		_, err = jsoncall.CallFunc(addUserContext, `[{}]`, nilContext, jsoncall.WithCheckContext())
+		assert.NoError(t, err)
 
-// Test calling of functions.
-func TestCallFunc(t *testing.T) {
-	t.Run("should support returning a value", func(t *testing.T) {
-		add := func(a, b int) int { return a + b }
-		v, err := jsoncall.CallFunc(add, `[1,2]`)
+		vals, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(addUserContext), `[{}]`, jsoncall.WithContextFunc(nil))
 		assert.NoError(t, err)
-		assert.Len(t, v, 1)
-		assert.Equal(t, 3, v[0].Interface())
-	})
+		assert.Equal(t, context.Background(), vals[0].Interface())
 
-	t.Run("should support returning errors", func(t *testing.T) {
-		add := func(a, b int) error { return errors.New("boom") }
-		_, err := jsoncall.CallFunc(add, `[1,2]`)
-		assert.EqualError(t, err, `boom`)
+		vals, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(addUserContext), `[{}]`, jsoncall.WithContext(nil))
+		assert.NoError(t, err)
+		assert.Equal(t, context.Background(), vals[0].Interface())
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(addUserRequestContext), `[{}]`, nilContext)
+		assert.EqualError(t, err, `Context is not assignable to the context parameter`)
 	})
 
-	t.Run("should support returning values and errors", func(t *testing.T) {
-		add := func(a, b int) (int, error) { return a + b, nil }
-		v, err := jsoncall.CallFunc(add, `[1,2]`)
+	t.Run("should support custom context interfaces", func(t *testing.T) {
+		vals, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(addUserRequestContext), `[{ "name": "Tobi" }]`, jsoncall.WithContextFunc(func() context.Context {
+			return requestContext{context.Background(), "123"}
+		}))
 		assert.NoError(t, err)
-		assert.Equal(t, 3, v[0].Interface())
+		assert.Len(t, vals, 2)
+		assert.Equal(t, "123", vals[0].Interface().(RequestContext).RequestID())
 	})
 
-	t.Run("should support returning multiple values", func(t *testing.T) {
-		minmax := func(a, b int) (min, max int) { return a, b }
+	t.Run("should error when the context is not assignable", func(t *testing.T) {
+		_, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(addUserRequestContext), `[{ "name": "Tobi" }]`)
+		assert.EqualError(t, err, `Context is not assignable to the context parameter`)
+	})
+
+	t.Run("should support additional context types via WithContextTypes", func(t *testing.T) {
+		logger := reflect.TypeOf((*Logger)(nil)).Elem()
+
+		vals, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(addUserLogger), `[{ "name": "Tobi" }]`, jsoncall.WithContextTypes(logger), jsoncall.WithContextFunc(func() context.Context {
+			return contextLogger{context.Background()}
+		}))
+		assert.NoError(t, err)
+		assert.Len(t, vals, 2)
+		assert.Implements(t, (*Logger)(nil), vals[0].Interface())
+		assert.Equal(t, "Tobi", vals[1].Interface().(User).Name)
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(addUserLogger), `[{ "name": "Tobi" }]`)
+		assert.EqualError(t, err, `Too few arguments: expected 2, got 1`)
+	})
+
+	t.Run("should support slices of structs", func(t *testing.T) {
+		vals, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(addUsers), `[[{ "name": "Tobi" }, { "name": "Loki" }]]`)
+		assert.NoError(t, err)
+		assert.Len(t, vals, 1)
+		assert.Equal(t, "Tobi", vals[0].Interface().([]User)[0].Name)
+	})
+
+	t.Run("should support slices of pointers to structs", func(t *testing.T) {
+		vals, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(addUserPointers), `[[{ "name": "Tobi" }, null, { "name": "Loki" }]]`)
+		assert.NoError(t, err)
+		assert.Len(t, vals, 1)
+		users := vals[0].Interface().([]*User)
+		assert.Len(t, users, 3)
+		assert.Equal(t, "Tobi", users[0].Name)
+		assert.Nil(t, users[1])
+		assert.Equal(t, "Loki", users[2].Name)
+	})
+
+	t.Run("should not count null elements towards arity", func(t *testing.T) {
+		_, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(addUserPointers), `[[null, null], null]`)
+		assert.EqualError(t, err, `Too many arguments: expected 1, got 2`)
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(addUserPointers), `[]`)
+		assert.EqualError(t, err, `Too few arguments: expected 1, got 0`)
+	})
+
+	t.Run("should support double pointers to structs", func(t *testing.T) {
+		vals, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(addUserDoublePointer), `[{ "name": "Tobi" }]`)
+		assert.NoError(t, err)
+		assert.Equal(t, "Tobi", (*vals[0].Interface().(**User)).Name)
+
+		vals, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(addUserDoublePointer), `[null]`)
+		assert.NoError(t, err)
+		assert.Nil(t, vals[0].Interface().(**User))
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(addUserDoublePointer), `["Tobi"]`)
+		assert.EqualError(t, err, `Incorrect type string, expected object`)
+	})
+
+	t.Run("should support custom unmarshalers", func(t *testing.T) {
+		vals, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(setNickname), `["Tobi"]`)
+		assert.NoError(t, err)
+		assert.Equal(t, sql.NullString{String: "Tobi", Valid: true}, vals[0].Interface().(NullString).NullString)
+
+		vals, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(setNickname), `[null]`)
+		assert.NoError(t, err)
+		assert.False(t, vals[0].Interface().(NullString).Valid)
+	})
+
+	t.Run("should return errors from custom unmarshalers", func(t *testing.T) {
+		_, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(setNickname), `[5]`)
+		assert.EqualError(t, err, `expected string or null: json: cannot unmarshal number into Go value of type string`)
+	})
+
+	t.Run("should support configuring the decoder via WithDecoderConfig", func(t *testing.T) {
+		fn := func(v interface{}) {}
+		vals, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[12345678901234567890]`, jsoncall.WithDecoderConfig(func(d *json.Decoder) {
+			d.UseNumber()
+		}))
+		assert.NoError(t, err)
+		assert.Equal(t, json.Number("12345678901234567890"), vals[0].Interface())
+
+		strict := jsoncall.WithDecoderConfig(func(d *json.Decoder) {
+			d.DisallowUnknownFields()
+		})
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(addUser), `[{ "name": "Tobi", "age": 5 }]`, strict)
+		assert.EqualError(t, err, `json: unknown field "age"`)
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(addUser), `[{ "name": 5 }]`, strict)
+		assert.EqualError(t, err, `Incorrect type number, expected string`)
+	})
+
+	t.Run("should decode the same as usual via WithStreamingDecode", func(t *testing.T) {
+		type Options struct{ Limit int }
+		optional := func(name string, opts Options) {}
+
+		cases := []struct {
+			fn      interface{}
+			args    string
+			options []jsoncall.Option
+		}{
+			{add, `[1, 2]`, nil},
+			{add, ` [ 1 , 2 ] `, nil},
+			{add, `[1]`, nil},
+			{add, `[1, 2, 3]`, nil},
+			{add, `[1, 2, 3]`, []jsoncall.Option{jsoncall.WithIgnoreExtraArguments()}},
+			{add, `["1", 2, 3]`, nil},
+			{add, `[1, "2"]`, nil},
+			{add, `[1, {]`, nil},
+			{add, `[1, 2] 3`, nil},
+			{add, `{}`, nil},
+			{add, `null`, nil},
+			{add, `[1, null]`, []jsoncall.Option{jsoncall.WithRejectNull()}},
+			{addUserContext, `[{ "name": "Tobi" }]`, nil},
+			{addUsers, `[[{ "name": "Tobi" }, { "name": "Loki" }]]`, nil},
+			{optional, `["Tobi"]`, []jsoncall.Option{jsoncall.WithOptionalStruct(1)}},
+			{optional, `["Tobi", { "Limit": 5 }]`, []jsoncall.Option{jsoncall.WithOptionalStruct(1)}},
+			{func() {}, `[]`, nil},
+			{func() {}, `[1]`, nil},
+		}
+
+		for _, c := range cases {
+			t.Run(c.args, func(t *testing.T) {
+				ft := reflect.TypeOf(c.fn)
+				expected, expectedErr := jsoncall.ArgumentsOfFunc(ft, c.args, c.options...)
+				actual, err := jsoncall.ArgumentsOfFunc(ft, c.args, append(c.options, jsoncall.WithStreamingDecode())...)
+
+				assert.Equal(t, expectedErr, err)
+				assert.Equal(t, len(expected), len(actual))
+				for i := range expected {
+					if ft.In(i).Kind() != reflect.Interface {
+						assert.Equal(t, expected[i].Interface(), actual[i].Interface())
+					}
+				}
+			})
+		}
+	})
+
+	t.Run("should wrap single values via WithCoerceScalarToSlice", func(t *testing.T) {
+		fn := func(id int, names []string, users []User) {}
+		coerce := jsoncall.WithCoerceScalarToSlice()
+
+		vals, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[1, "Tobi", { "name": "Loki" }]`, coerce)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, vals[0].Interface())
+		assert.Equal(t, []string{"Tobi"}, vals[1].Interface())
+		assert.Equal(t, []User{{Name: "Loki"}}, vals[2].Interface())
+
+		vals, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[1, ["Tobi", "Loki"], null]`, coerce)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Tobi", "Loki"}, vals[1].Interface())
+		assert.Nil(t, vals[2].Interface())
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[1, 5, []]`, coerce)
+		assert.EqualError(t, err, `Incorrect type number, expected string`)
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[1, "Tobi", []]`)
+		assert.EqualError(t, err, `Incorrect type string, expected array of strings`)
+	})
+
+	t.Run("should not wrap byte slices via WithCoerceScalarToSlice", func(t *testing.T) {
+		fn := func(b []byte) {}
+		vals, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `["aGk="]`, jsoncall.WithCoerceScalarToSlice())
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("hi"), vals[0].Interface())
+	})
+
+	t.Run("should default omitted structs via WithOptionalStruct", func(t *testing.T) {
+		type Options struct {
+			Limit   int  `json:"limit"`
+			Verbose bool `json:"verbose"`
+		}
+
+		fn := func(ctx context.Context, query string, opts Options, page *Options) {}
+		optional := []jsoncall.Option{jsoncall.WithOptionalStruct(1), jsoncall.WithOptionalStruct(2)}
+
+		vals, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `["ferrets"]`, optional...)
+		assert.NoError(t, err)
+		assert.Len(t, vals, 4)
+		assert.Equal(t, Options{}, vals[2].Interface())
+		assert.Nil(t, vals[3].Interface())
+
+		vals, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `["ferrets", { "limit": 5 }]`, optional...)
+		assert.NoError(t, err)
+		assert.Equal(t, Options{Limit: 5}, vals[2].Interface())
+		assert.Nil(t, vals[3].Interface())
+
+		vals, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `["ferrets", { "limit": 5 }, { "verbose": true }]`, optional...)
+		assert.NoError(t, err)
+		assert.Equal(t, &Options{Verbose: true}, vals[3].Interface())
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[]`, optional...)
+		assert.EqualError(t, err, `Too few arguments: expected 1, 2 or 3, got 0`)
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `["a", {}, {}, {}]`, optional...)
+		assert.EqualError(t, err, `Too many arguments: expected 1, 2 or 3, got 4`)
+	})
+
+	t.Run("should only omit trailing optional structs", func(t *testing.T) {
+		type Options struct{}
+		fn := func(opts Options, query string) {}
+
+		_, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `["ferrets"]`, jsoncall.WithOptionalStruct(0))
+		assert.EqualError(t, err, `Too few arguments: expected 2, got 1`)
+	})
+
+	t.Run("should inject leading values via WithInjected", func(t *testing.T) {
+		type DB struct{ Name string }
+		db := &DB{Name: "primary"}
+		fn := func(ctx context.Context, db *DB, id int) {}
+
+		vals, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[5]`, jsoncall.WithInjected(reflect.ValueOf(db)))
+		assert.NoError(t, err)
+		assert.Len(t, vals, 3)
+		assert.Equal(t, db, vals[1].Interface())
+		assert.Equal(t, 5, vals[2].Interface())
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[5, 6]`, jsoncall.WithInjected(reflect.ValueOf(db)))
+		assert.EqualError(t, err, `Too many arguments: expected 1, got 2`)
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[5]`, jsoncall.WithInjected(reflect.ValueOf("nope")))
+		assert.Equal(t, jsoncall.ErrInjectedType, err)
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[]`, jsoncall.WithInjected(reflect.ValueOf(db), reflect.ValueOf(1), reflect.ValueOf(2)))
+		assert.Equal(t, jsoncall.ErrInjectedType, err)
+	})
+
+	t.Run("should decode promoted fields of embedded structs", func(t *testing.T) {
+		type Base struct {
+			ID int `json:"id"`
+		}
+
+		type Member struct {
+			Base
+			*Account
+			Name string `json:"name"`
+		}
+
+		fn := func(m Member) {}
+		args := `[{ "id": 1, "name": "Tobi", "UserName": "tobi" }]`
+
+		vals, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), args)
+		assert.NoError(t, err)
+		m := vals[0].Interface().(Member)
+		assert.Equal(t, 1, m.ID)
+		assert.Equal(t, "Tobi", m.Name)
+		assert.Equal(t, "tobi", m.UserName)
+
+		strict := jsoncall.WithDecoderConfig(func(d *json.Decoder) {
+			d.DisallowUnknownFields()
+		})
+
+		vals, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[{ "id": 1, "name": "Tobi" }]`, strict)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, vals[0].Interface().(Member).ID)
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[{ "id": 1, "age": 5 }]`, strict)
+		assert.EqualError(t, err, `json: unknown field "age"`)
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[{ "id": "1" }]`)
+		assert.EqualError(t, err, `Incorrect type string, expected number`)
+	})
+
+	t.Run("should accept non-finite floats via WithAllowNonFiniteFloats", func(t *testing.T) {
+		allow := jsoncall.WithAllowNonFiniteFloats()
+
+		v, err := jsoncall.CallFunc(abs, `["-Infinity"]`, allow)
+		assert.NoError(t, err)
+		assert.True(t, math.IsInf(v[0].Interface().(float64), 1))
+
+		v, err = jsoncall.CallFunc(abs, `["NaN"]`, allow)
+		assert.NoError(t, err)
+		assert.True(t, math.IsNaN(v[0].Interface().(float64)))
+
+		b, err := jsoncall.MarshalResults(v, allow)
+		assert.NoError(t, err)
+		assert.Equal(t, `"NaN"`, string(b))
+
+		v, err = jsoncall.CallFunc(abs, `[-1.5]`, allow)
+		assert.NoError(t, err)
+		assert.Equal(t, 1.5, v[0].Interface())
+
+		fn := func(f *float32) {}
+		vals, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `["Infinity"]`, allow)
+		assert.NoError(t, err)
+		assert.True(t, math.IsInf(float64(*vals[0].Interface().(*float32)), 1))
+
+		_, err = jsoncall.CallFunc(abs, `["nope"]`, allow)
+		assert.EqualError(t, err, `Incorrect type string, expected number`)
+	})
+
+	t.Run("should reject non-finite floats by default", func(t *testing.T) {
+		_, err := jsoncall.CallFunc(abs, `["NaN"]`)
+		assert.EqualError(t, err, `Incorrect type string, expected number`)
+
+		_, err = jsoncall.MarshalResults([]reflect.Value{reflect.ValueOf(math.NaN())})
+		assert.Error(t, err)
+	})
+
+	t.Run("should decode durations from strings or numbers", func(t *testing.T) {
+		fn := func(timeout time.Duration, interval *time.Duration) {}
+
+		vals, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `["500ms", 1000000000]`)
+		assert.NoError(t, err)
+		assert.Equal(t, 500*time.Millisecond, vals[0].Interface())
+		assert.Equal(t, time.Second, *vals[1].Interface().(*time.Duration))
+
+		vals, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[1000000000, "1m30s"]`)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Second, vals[0].Interface())
+		assert.Equal(t, 90*time.Second, *vals[1].Interface().(*time.Duration))
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `["soon", null]`)
+		assert.EqualError(t, err, `Argument 0: time: invalid duration "soon"`)
+		assert.Equal(t, "invalid_argument", jsoncall.ErrorCategory(err))
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[true, null]`)
+		assert.EqualError(t, err, `Incorrect type bool, expected duration string or number`)
+	})
+
+	t.Run("should decode networking types from strings", func(t *testing.T) {
+		fn := func(ip net.IP, u *url.URL, addr netip.Addr) {}
+
+		cases := []struct {
+			args  string
+			error string
+		}{
+			{`["192.168.0.1", "https://apex.sh/docs?page=1", "::1"]`, ``},
+			{`["192.168.0.300", "https://apex.sh", "::1"]`, `Argument 0: invalid IP address: 192.168.0.300`},
+			{`["192.168.0.1", ":apex", "::1"]`, `Argument 1: parse ":apex": missing protocol scheme`},
+			{`["192.168.0.1", "https://apex.sh", "localhost"]`, `Argument 2: ParseAddr("localhost"): unable to parse IP`},
+			{`[1, "https://apex.sh", "::1"]`, `Incorrect type number, expected string`},
+			{`["192.168.0.1", {}, "::1"]`, `Incorrect type object, expected string`},
+			{`["192.168.0.1", "https://apex.sh", true]`, `Incorrect type bool, expected string`},
+		}
+
+		for _, c := range cases {
+			t.Run(c.args, func(t *testing.T) {
+				vals, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), c.args)
+
+				if c.error != "" {
+					assert.EqualError(t, err, c.error)
+					return
+				}
+
+				assert.NoError(t, err)
+				assert.Equal(t, "192.168.0.1", vals[0].Interface().(net.IP).String())
+				assert.Equal(t, "apex.sh", vals[1].Interface().(*url.URL).Host)
+				assert.Equal(t, "/docs", vals[1].Interface().(*url.URL).Path)
+				assert.Equal(t, netip.IPv6Loopback(), vals[2].Interface())
+			})
+		}
+
+		vals, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[null, null, "::1"]`)
+		assert.NoError(t, err)
+		assert.Nil(t, vals[0].Interface().(net.IP))
+		assert.Nil(t, vals[1].Interface().(*url.URL))
+	})
+
+	t.Run("should report failing elements via WithElementErrors", func(t *testing.T) {
+		fn := func(ids []int, users *[2]User) {}
+		elements := jsoncall.WithElementErrors()
+
+		_, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[[1, "two", 3], null]`, elements)
+		assert.EqualError(t, err, `Argument 0, element 1: Incorrect type string, expected number`)
+		assert.Equal(t, "incorrect_type", jsoncall.ErrorCategory(err))
+
+		var e *jsoncall.ElementError
+		assert.True(t, errors.As(err, &e))
+		assert.Equal(t, 0, e.Index)
+		assert.Equal(t, 1, e.Element)
+
+		b, err := json.Marshal(err)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{ "error": { "message": "Argument 0, element 1: Incorrect type string, expected number", "type": "incorrect_type", "argument": 0, "element": 1 } }`, string(b))
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[[1], [{}, { "name": 5 }]]`, elements)
+		assert.EqualError(t, err, `Argument 1, element 1: Incorrect type number, expected string`)
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `["nope", null]`, elements)
+		assert.EqualError(t, err, `Incorrect type string, expected array of numbers`)
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[[1, "two", 3], null]`)
+		assert.EqualError(t, err, `Incorrect type string, expected number`)
+	})
+
+	t.Run("should reject deeply nested arguments via WithMaxDepth", func(t *testing.T) {
+		fn := func(v interface{}) {}
+		limit := jsoncall.WithMaxDepth(3)
+
+		_, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[{ "a": [1] }]`, limit)
+		assert.NoError(t, err)
+
+		deep := strings.Repeat("[", 10000) + strings.Repeat("]", 10000)
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[{ "a": [[1]] }]`, limit)
+		assert.Equal(t, jsoncall.ErrMaxDepthExceeded, err)
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[`+deep+`]`, limit)
+		assert.Equal(t, jsoncall.ErrMaxDepthExceeded, err)
+		assert.Equal(t, http.StatusBadRequest, jsoncall.ErrorCode(err))
+
+		_, _, err = jsoncall.ArgumentsOfFuncMeta(reflect.TypeOf(fn), `[`+deep+`]`, limit)
+		assert.Equal(t, jsoncall.ErrMaxDepthExceeded, err)
+
+		_, err = jsoncall.Call1(func(v interface{}) error { return nil }, `[`+deep+`]`, limit)
+		assert.Equal(t, jsoncall.ErrMaxDepthExceeded, err)
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `["[[[[[["]`, limit)
+		assert.NoError(t, err)
+	})
+
+	t.Run("should decode fixed-size arrays", func(t *testing.T) {
+		fn := func(m [3]int) {}
+
+		vals, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[[1, 2, 3]]`)
+		assert.NoError(t, err)
+		assert.Equal(t, [3]int{1, 2, 3}, vals[0].Interface())
+
+		vals, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[[1, 2]]`)
+		assert.NoError(t, err)
+		assert.Equal(t, [3]int{1, 2, 0}, vals[0].Interface())
+	})
+
+	t.Run("should apply options to a single param via WithParamOptions", func(t *testing.T) {
+		fn := func(a, b interface{}) {}
+
+		useNumber := jsoncall.WithDecoderConfig(func(d *json.Decoder) {
+			d.UseNumber()
+		})
+
+		vals, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[1, 2]`, jsoncall.WithParamOptions(1, useNumber))
+		assert.NoError(t, err)
+		assert.Equal(t, 1.0, vals[0].Interface())
+		assert.Equal(t, json.Number("2"), vals[1].Interface())
+
+		strict := jsoncall.WithDecoderConfig(func(d *json.Decoder) {
+			d.DisallowUnknownFields()
+		})
+
+		ctxFn := func(ctx context.Context, lenient, strict User) {}
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(ctxFn), `[{ "nickname": "T" }, { "name": "Tobi" }]`, jsoncall.WithParamOptions(1, strict))
+		assert.NoError(t, err)
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(ctxFn), `[{ "name": "Tobi" }, { "nickname": "T" }]`, jsoncall.WithParamOptions(1, strict))
+		assert.EqualError(t, err, `json: unknown field "nickname"`)
+	})
+
+	t.Run("should merge param options over the call's options", func(t *testing.T) {
+		fn := func(a, b *int, c bool) {}
+		options := []jsoncall.Option{
+			jsoncall.WithRejectNull(),
+			jsoncall.WithParamOptions(2, jsoncall.WithRejectNull(), jsoncall.WithCoerceBool()),
+		}
+
+		vals, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[null, 1, 1]`, options...)
+		assert.NoError(t, err)
+		assert.True(t, vals[2].Interface().(bool))
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[null, 1, 2]`, options...)
+		assert.EqualError(t, err, `Incorrect type number, expected boolean`)
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[null, 1, null]`, options...)
+		assert.True(t, errors.Is(err, jsoncall.ErrNullNotAllowed))
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(func(a, b bool) {}), `[1, 1]`, options...)
+		assert.EqualError(t, err, `Incorrect type number, expected boolean`, "should not leak to other params")
+
+		v, err := jsoncall.DecodeArgument(reflect.TypeOf(true), json.RawMessage(`1`), jsoncall.WithParamOptions(0, jsoncall.WithCoerceBool()))
+		assert.NoError(t, err)
+		assert.True(t, v.Interface().(bool))
+
+		b, err := jsoncall.Call1(func(flag bool) bool { return flag }, `[1]`, jsoncall.WithParamOptions(0, jsoncall.WithCoerceBool()))
+		assert.NoError(t, err)
+		assert.True(t, b)
+	})
+
+	t.Run("should coerce 0 and 1 to booleans via WithCoerceBool", func(t *testing.T) {
+		fn := func(flag bool, opt *bool) {}
+		coerce := jsoncall.WithCoerceBool()
+
+		cases := []struct {
+			args  string
+			flag  bool
+			error string
+		}{
+			{`[0, null]`, false, ``},
+			{`[1, null]`, true, ``},
+			{`[true, null]`, true, ``},
+			{`[false, null]`, false, ``},
+			{`[2, null]`, false, `Incorrect type number, expected boolean`},
+			{`[1.0, null]`, false, `Incorrect type number, expected boolean`},
+			{`[-1, null]`, false, `Incorrect type number, expected boolean`},
+			{`["1", null]`, false, `Incorrect type string, expected boolean`},
+		}
+
+		for _, c := range cases {
+			t.Run(c.args, func(t *testing.T) {
+				vals, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), c.args, coerce)
+
+				if c.error != "" {
+					assert.EqualError(t, err, c.error)
+					assert.Equal(t, "incorrect_type", jsoncall.ErrorCategory(err))
+					return
+				}
+
+				assert.NoError(t, err)
+				assert.Equal(t, c.flag, vals[0].Interface())
+			})
+		}
+
+		vals, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[1, 0]`, coerce)
+		assert.NoError(t, err)
+		assert.False(t, *vals[1].Interface().(*bool))
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[1, null]`)
+		assert.EqualError(t, err, `Incorrect type number, expected boolean`)
+	})
+
+	t.Run("should report integers out of range", func(t *testing.T) {
+		type age uint8
+
+		cases := []struct {
+			fn    interface{}
+			args  string
+			error string
+		}{
+			{func(age uint8) {}, `[300]`, `Argument 0: value 300 out of range for uint8`},
+			{func(name string, n int8) {}, `["Tobi", 128]`, `Argument 1: value 128 out of range for int8`},
+			{func(n int8) {}, `[-129]`, `Argument 0: value -129 out of range for int8`},
+			{func(n uint) {}, `[-1]`, `Argument 0: value -1 out of range for uint`},
+			{func(n *uint16) {}, `[65536]`, `Argument 0: value 65536 out of range for uint16`},
+			{func(n age) {}, `[256]`, `Argument 0: value 256 out of range for uint8`},
+			{func(n int64) {}, `[9223372036854775808]`, `Argument 0: value 9223372036854775808 out of range for int64`},
+			{func(n int8) {}, `[1e3]`, `Argument 0: value 1000 out of range for int8`},
+			{func(n uint8) {}, `[2.56E2]`, `Argument 0: value 256 out of range for uint8`},
+			{func(n uint) {}, `[-1e2]`, `Argument 0: value -100 out of range for uint`},
+			{func(n int64) {}, `[1e100]`, `Argument 0: value 1e100 out of range for int64`},
+			{func(n uint8) {}, `[1.5]`, `Incorrect type number 1.5, expected number`},
+			{func(n int8) {}, `[1e2]`, `Incorrect type number 1e2, expected number`},
+			{func(n int8) {}, `[1.5e3]`, `Argument 0: value 1500 out of range for int8`},
+			{func(v struct{ A uint8 }) {}, `[{ "A": 300 }]`, `Argument 0: value 300 out of range for uint8 at "A"`},
+			{func(v struct{ B struct{ A int8 } }) {}, `[{ "B": { "A": -200 } }]`, `Argument 0: value -200 out of range for int8 at "B.A"`},
+			{func(v struct{ A uint8 }) {}, `[{ "A": 1.5 }]`, `Incorrect type number 1.5, expected number`},
+		}
+
+		for _, c := range cases {
+			t.Run(c.args, func(t *testing.T) {
+				_, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(c.fn), c.args)
+				assert.EqualError(t, err, c.error)
+			})
+		}
+
+		_, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(func(n uint8) {}), `[300]`)
+		assert.True(t, errors.Is(err, jsoncall.ErrOutOfRange))
+		assert.Equal(t, 400, jsoncall.ErrorCode(err))
+
+		var e *jsoncall.RangeError
+		assert.True(t, errors.As(err, &e))
+		assert.Equal(t, "300", e.Value)
+		assert.Equal(t, reflect.Uint8, e.Kind)
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(func(n uint8) {}), `[1e3]`, jsoncall.WithStrictIntegers())
+		assert.EqualError(t, err, `Argument 0: value 1000 out of range for uint8`)
+
+		nested := []struct {
+			fn    interface{}
+			args  string
+			value string
+			kind  reflect.Kind
+		}{
+			{func(b []uint8) {}, `[[1, 300]]`, "300", reflect.Uint8},
+			{func(m map[string]int8) {}, `[{ "a": 1e3 }]`, "1000", reflect.Int8},
+			{func(v struct{ A []int16 }) {}, `[{ "A": [1, -4e4] }]`, "-40000", reflect.Int16},
+		}
+
+		for _, c := range nested {
+			_, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(c.fn), c.args)
+			var e *jsoncall.RangeError
+			assert.True(t, errors.As(err, &e), c.args)
+			assert.Equal(t, c.value, e.Value)
+			assert.Equal(t, c.kind, e.Kind)
+		}
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(func(n []uint16) {}), `[[1, 2, 70000]]`, jsoncall.WithElementErrors())
+		assert.EqualError(t, err, `Argument 0, element 2: value 70000 out of range for uint16`)
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(func(v struct{ A uint8 }) {}), `[{ "A": 300 }]`)
+		assert.True(t, errors.As(err, &e))
+		assert.Equal(t, "A", e.Field)
+
+		vals, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(func(a uint8, b int8) {}), `[255, -128]`)
+		assert.NoError(t, err)
+		assert.Equal(t, uint8(255), vals[0].Interface())
+		assert.Equal(t, int8(-128), vals[1].Interface())
+	})
+
+	t.Run("should reject fractional integers via WithStrictIntegers", func(t *testing.T) {
+		strict := jsoncall.WithStrictIntegers()
+
+		cases := []struct {
+			args   string
+			output int
+			error  string
+		}{
+			{`[1, 2]`, 3, ``},
+			{`[1.0, 2]`, 3, ``},
+			{`[1e2, 2]`, 102, ``},
+			{`[-1.55E1, 2]`, 0, `Argument 0: Expected an integer, got -1.55E1`},
+			{`[1.5, 2]`, 0, `Argument 0: Expected an integer, got 1.5`},
+			{`[1, 1e-1]`, 0, `Argument 1: Expected an integer, got 1e-1`},
+			{`[1e30, 2]`, 0, `Argument 0: value 1e30 out of range for int`},
+			{`["1", 2]`, 0, `Incorrect type string, expected number`},
+		}
+
+		for _, c := range cases {
+			t.Run(c.args, func(t *testing.T) {
+				v, err := jsoncall.CallFunc(add, c.args, strict)
+
+				if c.error != "" {
+					assert.EqualError(t, err, c.error)
+					return
+				}
+
+				assert.NoError(t, err)
+				assert.Equal(t, c.output, v[0].Interface())
+			})
+		}
+
+		_, err := jsoncall.CallFunc(add, `[1.5, 2]`, strict)
+		assert.True(t, errors.Is(err, jsoncall.ErrNotInteger))
+		assert.Equal(t, "not_integer", jsoncall.ErrorCategory(err))
+
+		_, err = jsoncall.CallFunc(add, `[1.0, 2]`)
+		assert.EqualError(t, err, `Incorrect type number 1.0, expected number`)
+
+		fn := func(n *uint8) {}
+		vals, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[2.0]`, strict)
+		assert.NoError(t, err)
+		assert.Equal(t, uint8(2), *vals[0].Interface().(*uint8))
+	})
+
+	t.Run("should enforce array lengths via WithStrictArrayLength", func(t *testing.T) {
+		fn := func(id string, m *[3]int) {}
+
+		vals, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `["a", [1, 2, 3]]`, jsoncall.WithStrictArrayLength())
+		assert.NoError(t, err)
+		assert.Equal(t, &[3]int{1, 2, 3}, vals[1].Interface())
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `["a", [1, 2]]`, jsoncall.WithStrictArrayLength())
+		assert.EqualError(t, err, `Argument 1: Incorrect array length: expected 3, got 2`)
+		assert.True(t, errors.Is(err, jsoncall.ErrArrayLength))
+		assert.Equal(t, "array_length", jsoncall.ErrorCategory(err))
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `["a", [1, 2, 3, 4]]`, jsoncall.WithStrictArrayLength())
+		assert.EqualError(t, err, `Argument 1: Incorrect array length: expected 3, got 4`)
+
+		vals, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `["a", null]`, jsoncall.WithStrictArrayLength())
+		assert.NoError(t, err)
+		assert.Nil(t, vals[1].Interface())
+	})
+
+	t.Run("should decode big numbers losslessly", func(t *testing.T) {
+		fn := func(i *big.Int, f *big.Float) {}
+		huge := strings.Repeat("9", 400)
+
+		vals, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[`+huge+`, 1.5]`)
+		assert.NoError(t, err)
+		assert.Equal(t, huge, vals[0].Interface().(*big.Int).String())
+		assert.Equal(t, "1.5", vals[1].Interface().(*big.Float).String())
+
+		useNumber := jsoncall.WithDecoderConfig(func(d *json.Decoder) {
+			d.UseNumber()
+		})
+
+		vals, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `["`+huge+`", "2.25"]`, useNumber)
+		assert.NoError(t, err)
+		assert.Equal(t, huge, vals[0].Interface().(*big.Int).String())
+		assert.Equal(t, "2.25", vals[1].Interface().(*big.Float).String())
+	})
+
+	t.Run("should error on invalid big numbers", func(t *testing.T) {
+		fn := func(i big.Int) {}
+
+		_, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `["nope"]`)
+		assert.EqualError(t, err, `Incorrect type string, expected number`)
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[1.5]`)
+		var e jsoncall.UnmarshalError
+		assert.True(t, errors.As(err, &e))
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[{}]`)
+		assert.EqualError(t, err, `Incorrect type object, expected number`)
+	})
+
+	t.Run("should pass raw messages through unchanged", func(t *testing.T) {
+		fn := func(id int, raw json.RawMessage) {}
+		vals, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[1, { "name": "Tobi", "tags": [1, 2] }]`)
+		assert.NoError(t, err)
+		assert.Equal(t, `{ "name": "Tobi", "tags": [1, 2] }`, string(vals[1].Interface().(json.RawMessage)))
+	})
+
+	t.Run("should support pointers to raw messages", func(t *testing.T) {
+		fn := func(raw *json.RawMessage) {}
+		vals, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[[1, 2]]`)
+		assert.NoError(t, err)
+		assert.Equal(t, `[1, 2]`, string(*vals[0].Interface().(*json.RawMessage)))
+
+		vals, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[null]`)
+		assert.NoError(t, err)
+		assert.Nil(t, vals[0].Interface().(*json.RawMessage))
+	})
+
+	t.Run("should transform keys via WithKeyTransform", func(t *testing.T) {
+		keys := jsoncall.WithKeyTransform(camelCase)
+
+		vals, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(addAccount), `[{ "user_name": "tobi", "settings": { "dark_mode": true } }]`, keys)
+		assert.NoError(t, err)
+		a := vals[0].Interface().(Account)
+		assert.Equal(t, "tobi", a.UserName)
+		assert.True(t, a.Settings.DarkMode)
+
+		vals, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(addAccounts), `[[{ "user_name": "tobi" }, null]]`, keys)
+		assert.NoError(t, err)
+		assert.Equal(t, "tobi", vals[0].Interface().([]*Account)[0].UserName)
+
+		vals, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(addAccount), `[{ "user_name": "tobi" }]`)
+		assert.NoError(t, err)
+		assert.Equal(t, "", vals[0].Interface().(Account).UserName)
+	})
+
+	t.Run("should not transform keys of map fields", func(t *testing.T) {
+		type Team struct {
+			TeamName string
+			Labels   map[string]string
+			Members  []struct {
+				UserName string
+				Meta     interface{}
+			}
+		}
+
+		fn := func(team *Team) {}
+		args := `[{ "team_name": "pets", "labels": { "team_id": "x" }, "members": [{ "user_name": "tobi", "meta": { "first_name": "Tobi" } }] }]`
+		vals, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), args, jsoncall.WithKeyTransform(camelCase))
+		assert.NoError(t, err)
+
+		team := vals[0].Interface().(*Team)
+		assert.Equal(t, "pets", team.TeamName)
+		assert.Equal(t, map[string]string{"team_id": "x"}, team.Labels)
+		assert.Equal(t, "tobi", team.Members[0].UserName)
+		assert.Equal(t, map[string]interface{}{"first_name": "Tobi"}, team.Members[0].Meta)
+	})
+
+	t.Run("should not transform keys of non-struct parameters", func(t *testing.T) {
+		vals, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(addTags), `[{ "user_name": ["tobi"] }]`, jsoncall.WithKeyTransform(camelCase))
+		assert.NoError(t, err)
+		assert.Equal(t, map[string][]string{"user_name": {"tobi"}}, vals[0].Interface())
+	})
+
+	t.Run("should support wrapping scalar map values via WithScalarMapValuesToSlice", func(t *testing.T) {
+		vals, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(addTags), `[{ "k": "v" }]`, jsoncall.WithScalarMapValuesToSlice())
+		assert.NoError(t, err)
+		assert.Equal(t, map[string][]string{"k": {"v"}}, vals[0].Interface())
+
+		vals, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(addTags), `[{ "k": ["v1", "v2"] }]`, jsoncall.WithScalarMapValuesToSlice())
+		assert.NoError(t, err)
+		assert.Equal(t, map[string][]string{"k": {"v1", "v2"}}, vals[0].Interface())
+	})
+
+	t.Run("should error on scalar map values by default", func(t *testing.T) {
+		_, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(addTags), `[{ "k": "v" }]`)
+		assert.EqualError(t, err, `Incorrect type string, expected array of strings`)
+	})
+
+	t.Run("should support validation via WithValidator", func(t *testing.T) {
+		var indices []int
+		validate := jsoncall.WithValidator(func(i int, v reflect.Value) error {
+			indices = append(indices, i)
+			if s, ok := v.Interface().(Species); ok && s != Ferret && s != Cat {
+				return fmt.Errorf("invalid species %q", s)
+			}
+			return nil
+		})
+
+		_, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(addPetSpecies), `["Tobi", "ferret"]`, validate)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{0, 1}, indices)
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(addPetSpecies), `["Tobi", "dog"]`, validate)
+		assert.EqualError(t, err, `Argument 1: invalid species "dog"`)
+
+		var e *jsoncall.ArgumentError
+		assert.True(t, errors.As(err, &e))
+		assert.Equal(t, 1, e.Index)
+	})
+
+	t.Run("should inspect raw params via WithPreDecode", func(t *testing.T) {
+		var raws []string
+		audit := jsoncall.WithPreDecode(func(i int, raw json.RawMessage) error {
+			raws = append(raws, fmt.Sprintf("%d=%s", i, raw))
+			return nil
+		})
+
+		fn := func(ctx context.Context, name string, tags []string) {}
+		_, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `["Tobi", "ferret"]`, audit, jsoncall.WithCoerceScalarToSlice())
+		assert.NoError(t, err)
+		assert.Equal(t, []string{`0="Tobi"`, `1="ferret"`}, raws)
+
+		raws = nil
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(add), `[1, 2]`, audit, jsoncall.WithStreamingDecode())
+		assert.NoError(t, err)
+		assert.Equal(t, []string{`0=1`, `1=2`}, raws)
+
+		raws = nil
+		_, err = jsoncall.CallFunc(func(sep string, parts ...string) {}, `["-", "a", "b"]`, audit)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{`0="-"`, `1="a"`, `2="b"`}, raws)
+	})
+
+	t.Run("should abort on errors from WithPreDecode", func(t *testing.T) {
+		var called bool
+		fn := func(name, password string) { called = true }
+
+		redact := jsoncall.WithPreDecode(func(i int, raw json.RawMessage) error {
+			if i == 1 && string(raw) != `"[redacted]"` {
+				return errors.New("password must be redacted")
+			}
+			return nil
+		})
+
+		_, err := jsoncall.CallFunc(fn, `["Tobi", "hunter2"]`, redact)
+		assert.EqualError(t, err, `Argument 1: password must be redacted`)
+		assert.False(t, called)
+
+		var e *jsoncall.ArgumentError
+		assert.True(t, errors.As(err, &e))
+		assert.Equal(t, 1, e.Index)
+
+		_, err = jsoncall.CallFunc(fn, `["Tobi", "[redacted]"]`, redact)
+		assert.NoError(t, err)
+		assert.True(t, called)
+	})
+
+	t.Run("should error on unsupported parameter types", func(t *testing.T) {
+		cases := []struct {
+			fn    interface{}
+			index int
+			kind  reflect.Kind
+		}{
+			{func(ch chan int) {}, 0, reflect.Chan},
+			{func(ctx context.Context, name string, ch *<-chan string) {}, 1, reflect.Chan},
+			{func(fn func(int) int) {}, 0, reflect.Func},
+			{func(name string, fns ...func()) {}, 1, reflect.Func},
+			{func(p unsafe.Pointer) {}, 0, reflect.UnsafePointer},
+		}
+
+		for _, c := range cases {
+			_, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(c.fn), `[]`)
+			assert.True(t, errors.Is(err, jsoncall.ErrUnsupportedParameterType))
+			assert.Equal(t, 500, jsoncall.ErrorCode(err))
+
+			var a *jsoncall.ArgumentError
+			assert.True(t, errors.As(err, &a))
+			assert.Equal(t, c.index, a.Index)
+
+			var e *jsoncall.ParameterTypeError
+			assert.True(t, errors.As(err, &e))
+			assert.Equal(t, c.kind, e.Kind)
+		}
+	})
+
+	t.Run("should allow injected functions", func(t *testing.T) {
+		fn := func(log func(string), name string) {}
+		args, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `["Tobi"]`, jsoncall.WithInjected(reflect.ValueOf(func(string) {})))
+		assert.NoError(t, err)
+		assert.Equal(t, "Tobi", args[1].Interface())
+	})
+
+	t.Run("should support variadic functions", func(t *testing.T) {
+		args, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(sum), `[1, 2, 3, 4]`)
+		assert.NoError(t, err)
+		assert.Len(t, args, 1)
+		assert.Equal(t, []int{1, 2, 3, 4}, args[0].Interface())
+	})
+
+	t.Run("should error when not a function", func(t *testing.T) {
+		_, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(1), `[]`)
+		assert.Equal(t, jsoncall.ErrNotFunction, err)
+
+		_, err = jsoncall.ArgumentsOfFunc(nil, `[]`)
+		assert.Equal(t, jsoncall.ErrNotFunction, err)
+	})
+}
+
+// Test arguments and their spans from a function signature.
+func TestArgumentsOfFuncMeta(t *testing.T) {
+	t.Run("should return the span of each argument", func(t *testing.T) {
+		args := `[ "Tobi" , { "name": "Loki" },5,  [1, 2] ]`
+		fn := func(string, User, int, []int) {}
+		vals, spans, err := jsoncall.ArgumentsOfFuncMeta(reflect.TypeOf(fn), args)
+		assert.NoError(t, err)
+		assert.Len(t, vals, 4)
+		assert.Len(t, spans, 4)
+		assert.Equal(t, `"Tobi"`, args[spans[0].Start:spans[0].End])
+		assert.Equal(t, `{ "name": "Loki" }`, args[spans[1].Start:spans[1].End])
+		assert.Equal(t, `5`, args[spans[2].Start:spans[2].End])
+		assert.Equal(t, `[1, 2]`, args[spans[3].Start:spans[3].End])
+	})
+
+	t.Run("should return spans on decoding errors", func(t *testing.T) {
+		args := `[1, "5"]`
+		_, spans, err := jsoncall.ArgumentsOfFuncMeta(reflect.TypeOf(add), args)
+		assert.EqualError(t, err, `Incorrect type string, expected number`)
+		assert.Equal(t, `"5"`, args[spans[1].Start:spans[1].End])
+	})
+
+	t.Run("should error when the input is invalid json", func(t *testing.T) {
+		_, _, err := jsoncall.ArgumentsOfFuncMeta(reflect.TypeOf(add), `[5, hey]`)
+		assert.EqualError(t, err, `Invalid JSON`)
+
+		_, _, err = jsoncall.ArgumentsOfFuncMeta(reflect.TypeOf(add), `[5, 1] 2`)
+		assert.EqualError(t, err, `Invalid JSON`)
+	})
+
+	t.Run("should error when not a function", func(t *testing.T) {
+		_, _, err := jsoncall.ArgumentsOfFuncMeta(reflect.TypeOf(1), `[]`)
+		assert.Equal(t, jsoncall.ErrNotFunction, err)
+
+		_, _, err = jsoncall.ArgumentsOfFuncMeta(nil, `[]`)
+		assert.Equal(t, jsoncall.ErrNotFunction, err)
+	})
+}
+
+// Test decoding of a single argument.
+func TestDecodeArgument(t *testing.T) {
+	t.Run("should decode values", func(t *testing.T) {
+		v, err := jsoncall.DecodeArgument(reflect.TypeOf(User{}), json.RawMessage(`{ "name": "Tobi" }`))
+		assert.NoError(t, err)
+		assert.Equal(t, User{Name: "Tobi"}, v.Interface())
+
+		v, err = jsoncall.DecodeArgument(reflect.TypeOf(net.IP{}), json.RawMessage(`"10.0.0.1"`))
+		assert.NoError(t, err)
+		assert.Equal(t, "10.0.0.1", v.Interface().(net.IP).String())
+	})
+
+	t.Run("should match the errors of ArgumentsOfFunc", func(t *testing.T) {
+		_, jsonErr := jsoncall.ArgumentsOfFunc(reflect.TypeOf(abs), `["5"]`)
+		_, err := jsoncall.DecodeArgument(reflect.TypeOf(0.0), json.RawMessage(`"5"`))
+		assert.EqualError(t, err, `Incorrect type string, expected number`)
+		assert.Equal(t, jsonErr, err)
+	})
+
+	t.Run("should apply options", func(t *testing.T) {
+		useNumber := jsoncall.WithDecoderConfig(func(d *json.Decoder) {
+			d.UseNumber()
+		})
+
+		v, err := jsoncall.DecodeArgument(reflect.TypeOf((*interface{})(nil)).Elem(), json.RawMessage(`5`), useNumber)
+		assert.NoError(t, err)
+		assert.Equal(t, json.Number("5"), v.Interface())
+
+		_, err = jsoncall.DecodeArgument(reflect.TypeOf(0), json.RawMessage(`null`), jsoncall.WithRejectNull())
+		assert.True(t, errors.Is(err, jsoncall.ErrNullNotAllowed))
+
+		_, err = jsoncall.DecodeArgument(reflect.TypeOf([]int{}), json.RawMessage(`[[1]]`), jsoncall.WithMaxDepth(1))
+		assert.Equal(t, jsoncall.ErrMaxDepthExceeded, err)
+	})
+
+	t.Run("should error on nil and unsupported types", func(t *testing.T) {
+		_, err := jsoncall.DecodeArgument(nil, json.RawMessage(`5`))
+		assert.Equal(t, jsoncall.ErrUnsupportedParameterType, err)
+
+		_, err = jsoncall.DecodeArgument(reflect.TypeOf(make(chan int)), json.RawMessage(`5`))
+		assert.EqualError(t, err, `Argument 0: Unsupported parameter type chan int of kind chan`)
+		assert.True(t, errors.Is(err, jsoncall.ErrUnsupportedParameterType))
+	})
+}
+
+// RequestOption is a functional-style option decoded from a JSON object.
+type RequestOption struct {
+	Retries int  `json:"retries"`
+	Verbose bool `json:"verbose"`
+}
+
+// request is a function following the trailing options pattern.
+func request(url string, opts ...RequestOption) (int, []RequestOption) {
+	return len(opts), opts
+}
+
+// Test calling variadic functions.
+func TestVariadic(t *testing.T) {
+	t.Run("should pass trailing params as variadic arguments", func(t *testing.T) {
+		v, err := jsoncall.CallFunc(sum, `[1, 2, 3, 4]`)
+		assert.NoError(t, err)
+		assert.Equal(t, 10, v[0].Interface())
+
+		v, err = jsoncall.CallFunc(sum, `[]`)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, v[0].Interface())
+	})
+
+	t.Run("should decode objects into struct options", func(t *testing.T) {
+		v, err := jsoncall.CallFunc(request, `["/pets", { "retries": 3 }, { "verbose": true }]`)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, v[0].Interface())
+		assert.Equal(t, []RequestOption{{Retries: 3}, {Verbose: true}}, v[1].Interface())
+	})
+
+	t.Run("should allow zero trailing options", func(t *testing.T) {
+		v, err := jsoncall.CallFunc(request, `["/pets"]`)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, v[0].Interface())
+		assert.Nil(t, v[1].Interface())
+
+		args, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(request), `["/pets"]`)
+		assert.NoError(t, err)
+		assert.Len(t, args, 2)
+		assert.True(t, args[1].IsNil())
+	})
+
+	t.Run("should decode interface elements", func(t *testing.T) {
+		fn := func(values ...interface{}) []interface{} { return values }
+		v, err := jsoncall.CallFunc(fn, `[1, "two", { "three": 3 }, null]`)
+		assert.NoError(t, err)
+		assert.Equal(t, []interface{}{1.0, "two", map[string]interface{}{"three": 3.0}, nil}, v[0].Interface())
+	})
+
+	t.Run("should decode pointer elements", func(t *testing.T) {
+		fn := func(opts ...*RequestOption) int { return opts[0].Retries }
+		v, err := jsoncall.CallFunc(fn, `[{ "retries": 5 }]`)
+		assert.NoError(t, err)
+		assert.Equal(t, 5, v[0].Interface())
+	})
+
+	t.Run("should support contexts, methods and streaming", func(t *testing.T) {
+		fn := func(ctx context.Context, url string, opts ...RequestOption) int { return len(opts) }
+		v, err := jsoncall.CallFunc(fn, `["/pets", {}, {}]`)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, v[0].Interface())
+
+		v, err = jsoncall.CallFunc(fn, `["/pets", {}, {}, {}]`, jsoncall.WithStreamingDecode())
+		assert.NoError(t, err)
+		assert.Equal(t, 3, v[0].Interface())
+
+		r := &petService{}
+		m, _ := reflect.TypeOf(r).MethodByName("AddAll")
+		_, err = jsoncall.CallMethod(r, m, `["Tobi", "Loki"]`)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Tobi", "Loki"}, r.names)
+	})
+
+	t.Run("should report the index of invalid elements", func(t *testing.T) {
+		_, err := jsoncall.CallFunc(request, `["/pets", {}, { "retries": "3" }]`)
+		assert.EqualError(t, err, `Incorrect type string, expected number`)
+
+		_, err = jsoncall.CallFunc(request, `["/pets", {}, null]`, jsoncall.WithRejectNull())
+		var e *jsoncall.ArgumentError
+		assert.True(t, errors.As(err, &e))
+		assert.Equal(t, 2, e.Index)
+
+		_, err = jsoncall.CallFunc(sum, `[1, null]`, jsoncall.WithRejectNull(), jsoncall.WithStreamingDecode())
+		assert.True(t, errors.As(err, &e))
+		assert.Equal(t, 1, e.Index)
+	})
+
+	t.Run("should error when leading params are missing", func(t *testing.T) {
+		_, err := jsoncall.CallFunc(request, `[]`)
+		assert.EqualError(t, err, `Too few arguments: expected 1, got 0`)
+		assert.True(t, errors.Is(err, jsoncall.ErrTooFewArguments))
+
+		_, err = jsoncall.CallFunc(request, `[]`, jsoncall.WithStreamingDecode())
+		assert.EqualError(t, err, `Too few arguments: expected 1, got 0`)
+	})
+}
+
+// AddRequest is a message-style request.
+type AddRequest struct {
+	A       int    `json:"a"`
+	B       int    `json:"b"`
+	Comment string `json:"-"`
+	note    string
+}
+
+// Test building structs from positional arguments.
+func TestWithStructFromPositional(t *testing.T) {
+	addRequest := func(req AddRequest) (int, error) { return req.A + req.B, nil }
+
+	t.Run("should decode a sub-array into the fields in order", func(t *testing.T) {
+		fn := func(name string, req *AddRequest) {}
+		args, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `["sum", [1, 2]]`, jsoncall.WithStructFromPositional(1))
+		assert.NoError(t, err)
+		assert.Equal(t, &AddRequest{A: 1, B: 2}, args[1].Interface())
+	})
+
+	t.Run("should accept trailing arguments for a final struct", func(t *testing.T) {
+		v, err := jsoncall.CallFunc(addRequest, `[1, 2]`, jsoncall.WithStructFromPositional(0))
+		assert.NoError(t, err)
+		assert.Equal(t, 3, v[0].Interface())
+
+		v, err = jsoncall.CallFunc(addRequest, `[[3, 4]]`, jsoncall.WithStructFromPositional(0))
+		assert.NoError(t, err)
+		assert.Equal(t, 7, v[0].Interface())
+	})
+
+	t.Run("should decode objects as usual", func(t *testing.T) {
+		v, err := jsoncall.CallFunc(addRequest, `[{ "a": 1, "b": 5 }]`, jsoncall.WithStructFromPositional(0))
+		assert.NoError(t, err)
+		assert.Equal(t, 6, v[0].Interface())
+
+		v, err = jsoncall.CallFunc(addRequest, `[null]`, jsoncall.WithStructFromPositional(0))
+		assert.NoError(t, err)
+		assert.Equal(t, 0, v[0].Interface())
+	})
+
+	t.Run("should error on count mismatches", func(t *testing.T) {
+		_, err := jsoncall.CallFunc(addRequest, `[1, 2, 3]`, jsoncall.WithStructFromPositional(0))
+		assert.EqualError(t, err, `Argument 0: Incorrect array length: expected 2, got 3`)
+		assert.True(t, errors.Is(err, jsoncall.ErrArrayLength))
+
+		_, err = jsoncall.CallFunc(addRequest, `[[1]]`, jsoncall.WithStructFromPositional(0))
+		assert.EqualError(t, err, `Argument 0: Incorrect array length: expected 2, got 1`)
+
+		_, err = jsoncall.CallFunc(addRequest, `[]`, jsoncall.WithStructFromPositional(0))
+		assert.EqualError(t, err, `Too few arguments: expected 1, got 0`)
+	})
+
+	t.Run("should error on field mismatches", func(t *testing.T) {
+		_, err := jsoncall.CallFunc(addRequest, `[1, "2"]`, jsoncall.WithStructFromPositional(0))
+		assert.EqualError(t, err, `Argument 0, element 1: Incorrect type string, expected number`)
+
+		var e *jsoncall.ElementError
+		assert.True(t, errors.As(err, &e))
+		assert.Equal(t, 1, e.Element)
+	})
+
+	t.Run("should run validators", func(t *testing.T) {
+		validate := jsoncall.WithValidator(func(i int, v reflect.Value) error {
+			if v.Interface().(AddRequest).B == 0 {
+				return errors.New("b is required")
+			}
+			return nil
+		})
+
+		_, err := jsoncall.CallFunc(addRequest, `[1, 0]`, jsoncall.WithStructFromPositional(0), validate)
+		assert.EqualError(t, err, `Argument 0: b is required`)
+	})
+
+	t.Run("should not apply to other parameters", func(t *testing.T) {
+		_, err := jsoncall.CallFunc(addRequest, `[[1, 2]]`)
+		assert.EqualError(t, err, `Incorrect type array, expected object`)
+
+		_, err = jsoncall.CallFunc(addRequest, `[[1, 2]]`, jsoncall.WithStructFromPositional(1))
+		assert.EqualError(t, err, `Incorrect type array, expected object`)
+	})
+}
+
+// Test spreading of trailing arguments into a final slice.
+func TestWithSpreadSlice(t *testing.T) {
+	s := &mathService{}
+	m, _ := reflect.TypeOf(s).MethodByName("Sum")
+
+	t.Run("should support the flattened form", func(t *testing.T) {
+		vals, err := jsoncall.ArgumentsOfMethod(m, `[1,2,3]`, jsoncall.WithSpreadSlice())
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 2, 3}, vals[1].Interface())
+
+		vals, err = jsoncall.ArgumentsOfMethod(m, `[1]`, jsoncall.WithSpreadSlice())
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1}, vals[1].Interface())
+
+		vals, err = jsoncall.ArgumentsOfMethod(m, `[]`, jsoncall.WithSpreadSlice())
+		assert.NoError(t, err)
+		assert.Equal(t, []int{}, vals[1].Interface())
+	})
+
+	t.Run("should support the slice form", func(t *testing.T) {
+		vals, err := jsoncall.ArgumentsOfMethod(m, `[[1,2,3]]`, jsoncall.WithSpreadSlice())
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 2, 3}, vals[1].Interface())
+	})
+
+	t.Run("should support leading params", func(t *testing.T) {
+		fn := func(name string, tags []string) {}
+
+		vals, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `["Tobi", "ferret", "pet"]`, jsoncall.WithSpreadSlice())
+		assert.NoError(t, err)
+		assert.Equal(t, "Tobi", vals[0].Interface())
+		assert.Equal(t, []string{"ferret", "pet"}, vals[1].Interface())
+
+		vals, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `["Tobi", ["ferret", "pet"]]`, jsoncall.WithSpreadSlice())
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"ferret", "pet"}, vals[1].Interface())
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[]`, jsoncall.WithSpreadSlice())
+		assert.EqualError(t, err, `Too few arguments: expected 2, got 0`)
+	})
+
+	t.Run("should not spread by default", func(t *testing.T) {
+		_, err := jsoncall.ArgumentsOfMethod(m, `[1,2,3]`)
+		assert.EqualError(t, err, `Too many arguments: expected 1, got 3`)
+	})
+}
+
+// Test arguments from a method signature.
+func TestArgumentsOfMethod(t *testing.T) {
+	s := &mathService{}
+
+	m, ok := reflect.TypeOf(s).MethodByName("Sum")
+	assert.True(t, ok)
+
+	vals, err := jsoncall.ArgumentsOfMethod(m, `[[1,2,3,4]]`)
+	assert.NoError(t, err)
+	assert.Implements(t, (*context.Context)(nil), vals[0].Interface(), "should have a context")
+	assert.Equal(t, []int{1, 2, 3, 4}, vals[1].Interface())
+}
+
+// Test validation of arguments.
+func TestValidate(t *testing.T) {
+	var called bool
+	fn := func(a, b int) { called = true }
+
+	t.Run("should return nil for valid arguments", func(t *testing.T) {
+		assert.NoError(t, jsoncall.Validate(fn, `[1, 2]`))
+	})
+
+	t.Run("should return decoding errors", func(t *testing.T) {
+		assert.EqualError(t, jsoncall.Validate(fn, `[1]`), `Too few arguments: expected 2, got 1`)
+		assert.EqualError(t, jsoncall.Validate(fn, `[1, "2"]`), `Incorrect type string, expected number`)
+		assert.EqualError(t, jsoncall.Validate(5, `[]`), `Must pass a function`)
+		assert.Equal(t, jsoncall.ErrNotFunction, jsoncall.Validate(nil, `[]`))
+	})
+
+	t.Run("should not invoke the function", func(t *testing.T) {
+		assert.False(t, called, "should not call the function")
+	})
+}
+
+// Test the number of JSON-consuming parameters.
+func TestJSONArity(t *testing.T) {
+	t.Run("should count function parameters", func(t *testing.T) {
+		assert.Equal(t, 0, jsoncall.JSONArity(reflect.TypeOf(func() {}), false))
+		assert.Equal(t, 2, jsoncall.JSONArity(reflect.TypeOf(add), false))
+	})
+
+	t.Run("should exclude context", func(t *testing.T) {
+		assert.Equal(t, 1, jsoncall.JSONArity(reflect.TypeOf(addUserContext), false))
+	})
+
+	t.Run("should exclude context at any position", func(t *testing.T) {
+		fn := func(id int, ctx context.Context, name string) {}
+		assert.Equal(t, 2, jsoncall.JSONArity(reflect.TypeOf(fn), false))
+	})
+
+	t.Run("should exclude the receiver and context of methods", func(t *testing.T) {
+		m, _ := reflect.TypeOf(&mathService{}).MethodByName("Sum")
+		assert.Equal(t, 1, jsoncall.JSONArity(m.Type, true))
+	})
+}
+
+// Test detection of trailing error results.
+func TestHasErrorResult(t *testing.T) {
+	t.Run("should detect trailing errors", func(t *testing.T) {
+		assert.True(t, jsoncall.HasErrorResult(reflect.TypeOf(addUser)))
+		assert.True(t, jsoncall.HasErrorResult(reflect.TypeOf(func() (int, error) { return 0, nil })))
+		assert.True(t, jsoncall.HasErrorResult(reflect.TypeOf(func() (int, *codeError) { return 0, nil })))
+		assert.True(t, jsoncall.HasErrorResult(reflect.TypeOf(func() statusError { return "" })))
+	})
+
+	t.Run("should ignore other results", func(t *testing.T) {
+		assert.False(t, jsoncall.HasErrorResult(reflect.TypeOf(func() {})))
+		assert.False(t, jsoncall.HasErrorResult(reflect.TypeOf(add)))
+		assert.False(t, jsoncall.HasErrorResult(reflect.TypeOf(func() (error, int) { return nil, 0 })))
+		assert.False(t, jsoncall.HasErrorResult(reflect.TypeOf(func() []error { return nil })))
+		assert.False(t, jsoncall.HasErrorResult(reflect.TypeOf(5)))
+		assert.False(t, jsoncall.HasErrorResult(nil))
+	})
+
+	t.Run("should support methods", func(t *testing.T) {
+		m, _ := reflect.TypeOf(&mathService{}).MethodByName("Sum")
+		assert.False(t, jsoncall.HasErrorResult(m.Type))
+
+		m, _ = reflect.TypeOf(&resource{}).MethodByName("Close")
+		assert.True(t, jsoncall.HasErrorResult(m.Type))
+	})
+}
+
+// resource is a closer which records its name when closed.
+type resource struct {
+	name   string
+	closed *[]string
+}
+
+// Close implementation.
+func (r *resource) Close() error {
+	*r.closed = append(*r.closed, r.name)
+	return nil
+}
+
+// Test calling of functions.
+func TestCallFunc(t *testing.T) {
+	t.Run("should support returning a value", func(t *testing.T) {
+		add := func(a, b int) int { return a + b }
+		v, err := jsoncall.CallFunc(add, `[1,2]`)
+		assert.NoError(t, err)
+		assert.Len(t, v, 1)
+		assert.Equal(t, 3, v[0].Interface())
+	})
+
+	t.Run("should support returning errors", func(t *testing.T) {
+		add := func(a, b int) error { return errors.New("boom") }
+		_, err := jsoncall.CallFunc(add, `[1,2]`)
+		assert.EqualError(t, err, `boom`)
+	})
+
+	t.Run("should support returning values and errors", func(t *testing.T) {
+		add := func(a, b int) (int, error) { return a + b, nil }
+		v, err := jsoncall.CallFunc(add, `[1,2]`)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, v[0].Interface())
+	})
+
+	t.Run("should support returning multiple values", func(t *testing.T) {
+		minmax := func(a, b int) (min, max int) { return a, b }
+		v, err := jsoncall.CallFunc(minmax, `[1,2]`)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, v[0].Interface())
+		assert.Equal(t, 2, v[1].Interface())
+	})
+
+	t.Run("should support returning multiple values and errors", func(t *testing.T) {
+		minmax := func(a, b int) (min, max int, err error) { return a, b, nil }
 		v, err := jsoncall.CallFunc(minmax, `[1,2]`)
 		assert.NoError(t, err)
 		assert.Equal(t, 1, v[0].Interface())
-		assert.Equal(t, 2, v[1].Interface())
+		assert.Equal(t, 2, v[1].Interface())
+	})
+
+	t.Run("should support returning errors", func(t *testing.T) {
+		_, err := jsoncall.CallFunc(addPet, `["Tobi"]`)
+		assert.EqualError(t, err, `error adding pet`)
+	})
+
+	t.Run("should report calls via WithObserver", func(t *testing.T) {
+		var names []string
+		var errs []error
+		observe := jsoncall.WithObserver(func(name string, dur time.Duration, err error) {
+			assert.True(t, dur >= 0)
+			names = append(names, name)
+			errs = append(errs, err)
+		})
+
+		_, err := jsoncall.CallFunc(add, `[1, 2]`, observe)
+		assert.NoError(t, err)
+
+		_, err = jsoncall.CallFunc(addPet, `["Tobi"]`, observe)
+		assert.EqualError(t, err, `error adding pet`)
+
+		assert.Equal(t, []string{"github.com/tj/go-jsoncall_test.add", "github.com/tj/go-jsoncall_test.addPet"}, names)
+		assert.NoError(t, errs[0])
+		assert.EqualError(t, errs[1], `error adding pet`)
+	})
+
+	t.Run("should close results on error via WithCloseOnError", func(t *testing.T) {
+		var closed []string
+		open := func(name string, fail bool) (*resource, io.Closer, *resource, error) {
+			if fail {
+				return &resource{name, &closed}, &resource{name + "2", &closed}, nil, errors.New("boom")
+			}
+			return &resource{name, &closed}, nil, nil, nil
+		}
+
+		_, err := jsoncall.CallFunc(open, `["a", true]`, jsoncall.WithCloseOnError())
+		assert.EqualError(t, err, `boom`)
+		assert.Equal(t, []string{"a", "a2"}, closed)
+
+		closed = nil
+		v, err := jsoncall.CallFunc(open, `["b", false]`, jsoncall.WithCloseOnError())
+		assert.NoError(t, err)
+		assert.Len(t, v, 4)
+		assert.Empty(t, closed)
+
+		_, err = jsoncall.CallFunc(open, `["c", true]`)
+		assert.EqualError(t, err, `boom`)
+		assert.Empty(t, closed)
+	})
+
+	t.Run("should transform results via WithResultTransform", func(t *testing.T) {
+		type envelope struct {
+			Data interface{} `json:"data"`
+		}
+
+		wrap := jsoncall.WithResultTransform(func(v []reflect.Value) ([]reflect.Value, error) {
+			return []reflect.Value{reflect.ValueOf(envelope{Data: v[0].Interface()})}, nil
+		})
+
+		v, err := jsoncall.CallFunc(add, `[1, 2]`, wrap)
+		assert.NoError(t, err)
+		assert.Equal(t, envelope{Data: 3}, v[0].Interface())
+
+		_, err = jsoncall.CallFunc(addPet, `["Tobi"]`, wrap)
+		assert.EqualError(t, err, `error adding pet`)
+
+		fail := jsoncall.WithResultTransform(func(v []reflect.Value) ([]reflect.Value, error) {
+			return nil, errors.New("transform failed")
+		})
+
+		var observed error
+		observe := jsoncall.WithObserver(func(name string, dur time.Duration, err error) {
+			observed = err
+		})
+
+		_, err = jsoncall.CallFunc(add, `[1, 2]`, fail, observe)
+		assert.EqualError(t, err, `transform failed`)
+		assert.EqualError(t, observed, `transform failed`)
+	})
+
+	t.Run("should transform method results via WithResultTransform", func(t *testing.T) {
+		double := jsoncall.WithResultTransform(func(v []reflect.Value) ([]reflect.Value, error) {
+			return []reflect.Value{reflect.ValueOf(v[0].Interface().(int) * 2)}, nil
+		})
+
+		s := &mathService{}
+		m, _ := reflect.TypeOf(s).MethodByName("Sum")
+		v, err := jsoncall.CallMethod(s, m, `[[1, 2]]`, double)
+		assert.NoError(t, err)
+		assert.Equal(t, 6, v[0].Interface())
+	})
+
+	t.Run("should skip cancelled contexts via WithCheckContext", func(t *testing.T) {
+		var called bool
+		fn := func(ctx context.Context, u User) error { called = true; return nil }
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := jsoncall.CallFunc(fn, `[{}]`, jsoncall.WithContext(ctx), jsoncall.WithCheckContext())
+		assert.Equal(t, context.Canceled, err)
+		assert.False(t, called, "should not call the function")
+
+		_, err = jsoncall.CallFunc(fn, `[{}]`, jsoncall.WithContext(ctx))
+		assert.NoError(t, err)
+		assert.True(t, called, "should call the function")
+	})
+
+	t.Run("should return early on cancellation via WithAsyncCancellation", func(t *testing.T) {
+		started := make(chan struct{})
+		release := make(chan struct{})
+		finished := make(chan struct{})
+
+		fn := func(ctx context.Context, n int) int {
+			defer close(finished)
+			close(started)
+			<-release
+			return n
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			<-started
+			cancel()
+		}()
+
+		_, err := jsoncall.CallFunc(fn, `[1]`, jsoncall.WithContext(ctx), jsoncall.WithAsyncCancellation())
+		assert.Equal(t, context.Canceled, err)
+
+		select {
+		case <-finished:
+			t.Fatal("should return before the function finishes")
+		default:
+		}
+
+		close(release)
+		<-finished
+	})
+
+	t.Run("should return results before cancellation via WithAsyncCancellation", func(t *testing.T) {
+		fn := func(ctx context.Context, n int) (int, error) {
+			if n < 0 {
+				return 0, errors.New("negative")
+			}
+			return n * 2, nil
+		}
+
+		v, err := jsoncall.CallFunc(fn, `[2]`, jsoncall.WithAsyncCancellation())
+		assert.NoError(t, err)
+		assert.Equal(t, 4, v[0].Interface())
+
+		_, err = jsoncall.CallFunc(fn, `[-1]`, jsoncall.WithAsyncCancellation())
+		assert.EqualError(t, err, `negative`)
+
+		assert.Panics(t, func() {
+			jsoncall.CallFunc(func(ctx context.Context) { panic("boom") }, `[]`, jsoncall.WithAsyncCancellation())
+		})
+	})
+
+	t.Run("should return joined []error results via WithMultiError", func(t *testing.T) {
+		fn := func(names []string) (int, []error) {
+			var errs []error
+			for _, name := range names {
+				if name == "" {
+					errs = append(errs, errors.New("empty name"))
+				}
+			}
+			return len(names), errs
+		}
+
+		multi := jsoncall.WithMultiError()
+
+		v, err := jsoncall.CallFunc(fn, `[["Tobi", "Loki"]]`, multi)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, v[0].Interface())
+
+		_, err = jsoncall.CallFunc(fn, `[["", "Loki", ""]]`, multi)
+		assert.EqualError(t, err, "empty name\nempty name")
+
+		v, err = jsoncall.CallFunc(fn, `[["", "Loki"]]`)
+		assert.NoError(t, err)
+		assert.Len(t, v[1].Interface(), 1)
+
+		v, err = jsoncall.CallFunc(func() ([]error, int) { return []error{io.EOF}, 1 }, `[]`, multi)
+		assert.NoError(t, err, "only trailing results")
+		assert.Len(t, v, 2)
+
+		v, err = jsoncall.CallFunc(func() (int, []error) { return 1, []error{nil} }, `[]`, multi)
+		assert.NoError(t, err, "nil errors are ignored")
+		assert.Equal(t, 1, v[0].Interface())
+	})
+
+	t.Run("should return aggregate error results via WithMultiError", func(t *testing.T) {
+		fn := func(fail bool) (int, *validationErrors) {
+			if fail {
+				return 0, &validationErrors{errs: []error{errors.New("name required"), io.EOF}}
+			}
+			return 1, nil
+		}
+
+		multi := jsoncall.WithMultiError()
+
+		v, err := jsoncall.CallFunc(fn, `[false]`, multi)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, v[0].Interface())
+
+		_, err = jsoncall.CallFunc(fn, `[true]`, multi)
+		assert.EqualError(t, err, `2 validation errors`)
+		assert.True(t, errors.Is(err, io.EOF))
+		assert.IsType(t, &validationErrors{}, err)
+
+		_, err = jsoncall.CallFunc(func() (int, errorList) { return 0, errorList{io.EOF} }, `[]`, multi)
+		assert.Equal(t, io.EOF.Error(), err.Error())
+		assert.True(t, errors.Is(err, io.EOF))
+	})
+
+	t.Run("should return concrete error results as errors", func(t *testing.T) {
+		fn := func(code int) (string, *codeError) {
+			if code > 0 {
+				return "", &codeError{Code: code}
+			}
+			return "ok", nil
+		}
+
+		_, err := jsoncall.CallFunc(fn, `[5]`)
+		assert.EqualError(t, err, `error code 5`)
+
+		var e *codeError
+		assert.True(t, errors.As(err, &e))
+		assert.Equal(t, 5, e.Code)
+
+		v, err := jsoncall.CallFunc(fn, `[0]`)
+		assert.NoError(t, err)
+		assert.Equal(t, "ok", v[0].Interface())
+		assert.True(t, v[1].IsNil())
+
+		b, err := jsoncall.MarshalResults(v)
+		assert.NoError(t, err)
+		assert.Equal(t, `"ok"`, string(b))
+	})
+
+	t.Run("should return non-zero concrete error values as errors", func(t *testing.T) {
+		fn := func(status string) statusError { return statusError(status) }
+
+		_, err := jsoncall.CallFunc(fn, `["not found"]`)
+		assert.EqualError(t, err, `not found`)
+		assert.IsType(t, statusError(""), err)
+
+		v, err := jsoncall.CallFunc(fn, `[""]`)
+		assert.NoError(t, err)
+		assert.Len(t, v, 1)
+	})
+
+	t.Run("should return interface error results as errors", func(t *testing.T) {
+		type temporary interface {
+			error
+			Temporary() bool
+		}
+
+		fn := func(fail bool) (int, temporary) {
+			if fail {
+				return 0, &net.DNSError{Err: "timeout", IsTemporary: true}
+			}
+			return 1, nil
+		}
+
+		_, err := jsoncall.CallFunc(fn, `[true]`)
+		assert.EqualError(t, err, `lookup : timeout`)
+
+		v, err := jsoncall.CallFunc(fn, `[false]`)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, v[0].Interface())
 	})
 
-	t.Run("should support returning multiple values and errors", func(t *testing.T) {
-		minmax := func(a, b int) (min, max int, err error) { return a, b, nil }
-		v, err := jsoncall.CallFunc(minmax, `[1,2]`)
+	t.Run("should return nil errors as values on success", func(t *testing.T) {
+		v, err := jsoncall.CallFunc(addUser, `[{ "name": "Tobi" }]`)
+		assert.NoError(t, err)
+		assert.Len(t, v, 1)
+		assert.True(t, v[0].IsNil())
+
+		v, err = jsoncall.CallFunc(func() {}, `[]`)
+		assert.NoError(t, err)
+		assert.Len(t, v, 0)
+	})
+
+	t.Run("should invoke with live contexts via WithCheckContext", func(t *testing.T) {
+		v, err := jsoncall.CallFunc(addUserContext, `[{}]`, jsoncall.WithCheckContext())
+		assert.NoError(t, err)
+		assert.Len(t, v, 1)
+
+		v, err = jsoncall.CallFunc(add, `[1, 2]`, jsoncall.WithCheckContext())
+		assert.NoError(t, err)
+		assert.Equal(t, 3, v[0].Interface())
+	})
+
+	t.Run("should return errors as-is", func(t *testing.T) {
+		fn := func(name string) error { return &PetError{Name: name} }
+		_, err := jsoncall.CallFunc(fn, `["Tobi"]`)
+
+		var e *PetError
+		assert.True(t, errors.As(err, &e))
+		assert.Equal(t, "Tobi", e.Name)
+	})
+
+	t.Run("should support sentinel and wrapped errors", func(t *testing.T) {
+		fn := func() error { return errPetExists }
+		_, err := jsoncall.CallFunc(fn, `[]`)
+		assert.Equal(t, errPetExists, err)
+
+		wrapped := func() (int, error) { return 0, fmt.Errorf("adding: %w", &PetError{Name: "Loki"}) }
+		_, err = jsoncall.CallFunc(wrapped, `[]`)
+		assert.EqualError(t, err, `adding: invalid pet Loki`)
+
+		var e *PetError
+		assert.True(t, errors.As(err, &e))
+		assert.Equal(t, "Loki", e.Name)
+	})
+}
+
+// Test calling of function values.
+func TestCallValue(t *testing.T) {
+	t.Run("should support bound method values", func(t *testing.T) {
+		s := &stack[int]{}
+		fn := reflect.ValueOf(s).MethodByName("Push")
+		v, err := jsoncall.CallValue(fn, `[5]`)
 		assert.NoError(t, err)
 		assert.Equal(t, 1, v[0].Interface())
-		assert.Equal(t, 2, v[1].Interface())
+		assert.Equal(t, []int{5}, s.items)
+	})
+
+	t.Run("should support functions", func(t *testing.T) {
+		v, err := jsoncall.CallValue(reflect.ValueOf(add), `[1, 2]`)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, v[0].Interface())
+	})
+
+	t.Run("should support pre-decoded arguments", func(t *testing.T) {
+		fn := reflect.ValueOf(add)
+		v, err := jsoncall.CallValueArgs(fn, []reflect.Value{reflect.ValueOf(1), reflect.ValueOf(2)})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, v[0].Interface())
 	})
 
 	t.Run("should support returning errors", func(t *testing.T) {
-		_, err := jsoncall.CallFunc(addPet, `["Tobi"]`)
+		_, err := jsoncall.CallValue(reflect.ValueOf(addPet), `["Tobi"]`)
+		assert.EqualError(t, err, `error adding pet`)
+	})
+
+	t.Run("should error on non-function values", func(t *testing.T) {
+		_, err := jsoncall.CallValue(reflect.ValueOf(5), `[]`)
+		assert.EqualError(t, err, `Must pass a function`)
+	})
+}
+
+// Test calling of instantiated generic functions and methods.
+func TestGenerics(t *testing.T) {
+	t.Run("should reflect identically to regular functions", func(t *testing.T) {
+		assert.Equal(t, reflect.TypeOf(add), reflect.TypeOf(maxOf[int]))
+	})
+
+	t.Run("should support instantiated generic functions", func(t *testing.T) {
+		v, err := jsoncall.CallFunc(maxOf[int], `[1, 5]`)
+		assert.NoError(t, err)
+		assert.Equal(t, 5, v[0].Interface())
+
+		v, err = jsoncall.CallFunc(maxOf[string], `["a", "b"]`)
+		assert.NoError(t, err)
+		assert.Equal(t, "b", v[0].Interface())
+
+		_, err = jsoncall.CallFunc(maxOf[float64], `[1, "5"]`)
+		assert.EqualError(t, err, `Incorrect type string, expected number`)
+	})
+
+	t.Run("should support context in instantiated generic functions", func(t *testing.T) {
+		v, err := jsoncall.CallFunc(first[User], `[[{ "name": "Tobi" }]]`)
+		assert.NoError(t, err)
+		assert.Equal(t, "Tobi", v[0].Interface().(User).Name)
+	})
+
+	t.Run("should support methods of generic types", func(t *testing.T) {
+		s := &stack[User]{}
+		m, ok := reflect.TypeOf(s).MethodByName("Push")
+		assert.True(t, ok)
+
+		v, err := jsoncall.CallMethod(s, m, `[{ "name": "Tobi" }]`)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, v[0].Interface())
+		assert.Equal(t, "Tobi", s.items[0].Name)
+	})
+
+	t.Run("should support method values of generic types", func(t *testing.T) {
+		s := &stack[int]{}
+		v, err := jsoncall.CallFunc(s.Push, `[5]`)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, v[0].Interface())
+		assert.Equal(t, []int{5}, s.items)
+	})
+}
+
+// Test calling of method expressions.
+func TestCallMethodExpr(t *testing.T) {
+	t.Run("should pass the receiver and context", func(t *testing.T) {
+		s := &mathService{}
+		v, err := jsoncall.CallMethodExpr((*mathService).Sum, s, `[[1,2]]`)
+		assert.NoError(t, err)
+		assert.Len(t, v, 1)
+		assert.Equal(t, 3, v[0].Interface())
+	})
+
+	t.Run("should error on mismatched receivers", func(t *testing.T) {
+		_, err := jsoncall.CallMethodExpr((*mathService).Sum, mathService{}, `[[1,2]]`)
+		assert.Equal(t, jsoncall.ErrReceiverType, err)
+
+		_, err = jsoncall.CallMethodExpr(func() {}, &mathService{}, `[]`)
+		assert.Equal(t, jsoncall.ErrReceiverType, err)
+
+		_, err = jsoncall.CallMethodExpr((*mathService).Sum, nil, `[[1,2]]`)
+		assert.Equal(t, jsoncall.ErrReceiverType, err)
+	})
+
+	t.Run("should error on non-functions", func(t *testing.T) {
+		_, err := jsoncall.CallMethodExpr(5, &mathService{}, `[]`)
+		assert.Equal(t, jsoncall.ErrNotFunction, err)
+
+		_, err = jsoncall.CallMethodExpr(nil, &mathService{}, `[]`)
+		assert.Equal(t, jsoncall.ErrNotFunction, err)
+	})
+
+	t.Run("should treat the receiver as an argument of method expressions passed to CallFunc", func(t *testing.T) {
+		_, err := jsoncall.CallFunc((*mathService).Sum, `[[1,2]]`)
+		assert.EqualError(t, err, `Too few arguments: expected 2, got 1`)
+	})
+}
+
+// Test the context factory is only invoked when a context is expected.
+func TestWithContextFuncLaziness(t *testing.T) {
+	var calls int
+	factory := jsoncall.WithContextFunc(func() context.Context {
+		calls++
+		return context.Background()
+	})
+
+	t.Run("should not invoke the factory for functions without a context", func(t *testing.T) {
+		calls = 0
+
+		_, err := jsoncall.CallFunc(add, `[1, 2]`, factory)
+		assert.NoError(t, err)
+
+		_, err = jsoncall.CallFunc(add, `[1, 2]`, factory, jsoncall.WithCheckContext(), jsoncall.WithStreamingDecode())
+		assert.NoError(t, err)
+
+		_, err = jsoncall.CallFunc(func() {}, `[]`, factory)
+		assert.NoError(t, err)
+
+		_, _, err = jsoncall.CallFuncCtx(add, `[1, 2]`, factory)
+		assert.NoError(t, err)
+
+		_, err = jsoncall.CallFunc(add, `[1]`, factory)
+		assert.Error(t, err)
+
+		s := &petService{}
+		m, _ := reflect.TypeOf(s).MethodByName("Count")
+		_, err = jsoncall.CallMethod(s, m, `[]`, factory)
+		assert.NoError(t, err)
+
+		assert.Equal(t, 0, calls)
+	})
+
+	t.Run("should invoke the factory once for functions with a context", func(t *testing.T) {
+		calls = 0
+		_, err := jsoncall.CallFunc(addUserContext, `[{}]`, factory, jsoncall.WithCheckContext())
+		assert.NoError(t, err)
+		assert.Equal(t, 1, calls)
+	})
+}
+
+// Test combining options.
+func TestCombineOptions(t *testing.T) {
+	t.Run("should apply each option", func(t *testing.T) {
+		base := jsoncall.CombineOptions(jsoncall.WithIgnoreExtraArguments(), jsoncall.WithRejectNull())
+
+		v, err := jsoncall.CallFunc(add, `[1, 2, 3]`, base)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, v[0].Interface())
+
+		_, err = jsoncall.CallFunc(add, `[1, null]`, base)
+		assert.True(t, errors.Is(err, jsoncall.ErrNullNotAllowed))
+	})
+
+	t.Run("should let later options take precedence", func(t *testing.T) {
+		type key struct{}
+		fn := func(ctx context.Context) interface{} { return ctx.Value(key{}) }
+		ctx := func(v string) jsoncall.Option {
+			return jsoncall.WithContext(context.WithValue(context.Background(), key{}, v))
+		}
+
+		base := jsoncall.CombineOptions(ctx("base"))
+		variant := jsoncall.CombineOptions(base, ctx("variant"))
+
+		v, err := jsoncall.CallFunc(fn, `[]`, base)
+		assert.NoError(t, err)
+		assert.Equal(t, "base", v[0].Interface())
+
+		v, err = jsoncall.CallFunc(fn, `[]`, variant)
+		assert.NoError(t, err)
+		assert.Equal(t, "variant", v[0].Interface())
+
+		v, err = jsoncall.CallFunc(fn, `[]`, variant, ctx("call"))
+		assert.NoError(t, err)
+		assert.Equal(t, "call", v[0].Interface())
+	})
+
+	t.Run("should not be affected by changes to the given slice", func(t *testing.T) {
+		options := []jsoncall.Option{jsoncall.WithIgnoreExtraArguments()}
+		base := jsoncall.CombineOptions(options...)
+		options[0] = jsoncall.WithRejectNull()
+
+		v, err := jsoncall.CallFunc(add, `[1, 2, 3]`, base)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, v[0].Interface())
+	})
+}
+
+// Test checking functions are dispatchable.
+func TestCheckFunc(t *testing.T) {
+	t.Run("should accept supported shapes", func(t *testing.T) {
+		fns := []interface{}{
+			func() {},
+			add,
+			addUser,
+			addUserContext,
+			func(a int, ctx context.Context) (int, string, error) { return 0, "", nil },
+			sum,
+		}
+
+		for _, fn := range fns {
+			assert.NoError(t, jsoncall.CheckFunc(fn))
+		}
+	})
+
+	t.Run("should reject unsupported shapes", func(t *testing.T) {
+		cases := []struct {
+			fn  interface{}
+			err string
+		}{
+			{5, `Must pass a function`},
+			{func(a, b context.Context) {}, `Functions may have at most one context parameter`},
+			{func() (error, int) { return nil, 0 }, `Error results must be last`},
+			{func() (*codeError, int) { return nil, 0 }, `Error results must be last`},
+			{func(ch chan int) {}, `Argument 0: Unsupported parameter type chan int of kind chan`},
+			{func(name string, fn func() error) {}, `Argument 1: Unsupported parameter type func() error of kind func`},
+		}
+
+		for _, c := range cases {
+			assert.EqualError(t, jsoncall.CheckFunc(c.fn), c.err)
+		}
+	})
+
+	t.Run("should respect WithContextTypes", func(t *testing.T) {
+		fn := func(ctx context.Context, log Logger) {}
+		assert.NoError(t, jsoncall.CheckFunc(fn))
+		assert.Equal(t, jsoncall.ErrMultipleContexts, jsoncall.CheckFunc(fn, jsoncall.WithContextTypes(reflect.TypeOf((*Logger)(nil)).Elem())))
+	})
+}
+
+// Test calling of functions returning the injected context.
+func TestCallFuncCtx(t *testing.T) {
+	t.Run("should return the injected context", func(t *testing.T) {
+		parent := context.WithValue(context.Background(), requestIDKey{}, "abc")
+		ctx, v, err := jsoncall.CallFuncCtx(addUserContext, `[{ "name": "Tobi" }]`, jsoncall.WithContext(parent))
+		assert.NoError(t, err)
+		assert.Len(t, v, 1)
+		assert.Equal(t, "abc", ctx.Value(requestIDKey{}))
+	})
+
+	t.Run("should return a nil context for functions without one", func(t *testing.T) {
+		ctx, v, err := jsoncall.CallFuncCtx(add, `[1, 2]`)
+		assert.NoError(t, err)
+		assert.Nil(t, ctx)
+		assert.Equal(t, 3, v[0].Interface())
+	})
+
+	t.Run("should error on non-functions", func(t *testing.T) {
+		for _, fn := range []interface{}{nil, 5} {
+			ctx, v, err := jsoncall.CallFuncCtx(fn, `[]`)
+			assert.Equal(t, jsoncall.ErrNotFunction, err)
+			assert.Nil(t, ctx)
+			assert.Nil(t, v)
+		}
+	})
+
+	t.Run("should return the context alongside errors from the function", func(t *testing.T) {
+		fn := func(ctx context.Context) error { return errors.New("boom") }
+		ctx, _, err := jsoncall.CallFuncCtx(fn, `[]`)
+		assert.EqualError(t, err, `boom`)
+		assert.NotNil(t, ctx)
+	})
+
+	t.Run("should support methods via CallMethodCtx", func(t *testing.T) {
+		parent := context.WithValue(context.Background(), requestIDKey{}, "abc")
+		s := &mathService{}
+		m, _ := reflect.TypeOf(s).MethodByName("Sum")
+		ctx, v, err := jsoncall.CallMethodCtx(s, m, `[[1, 2]]`, jsoncall.WithContext(parent))
+		assert.NoError(t, err)
+		assert.Equal(t, 3, v[0].Interface())
+		assert.Equal(t, "abc", ctx.Value(requestIDKey{}))
+	})
+}
+
+// Test calling of functions which write their output.
+func TestCallFuncCapture(t *testing.T) {
+	greet := func(ctx context.Context, w io.Writer, name string) error {
+		if name == "" {
+			return errors.New("name required")
+		}
+		_, err := fmt.Fprintf(w, "Hello %s", name)
+		return err
+	}
+
+	t.Run("should return the bytes written", func(t *testing.T) {
+		b, err := jsoncall.CallFuncCapture(greet, `["Tobi"]`)
+		assert.NoError(t, err)
+		assert.Equal(t, "Hello Tobi", string(b))
+	})
+
+	t.Run("should inject the writer before other injected values", func(t *testing.T) {
+		fn := func(w io.Writer, prefix string, name string) {
+			fmt.Fprintf(w, "%s %s", prefix, name)
+		}
+
+		b, err := jsoncall.CallFuncCapture(fn, `["Tobi"]`, jsoncall.WithInjected(reflect.ValueOf("Hi")))
+		assert.NoError(t, err)
+		assert.Equal(t, "Hi Tobi", string(b))
+	})
+
+	t.Run("should return errors", func(t *testing.T) {
+		_, err := jsoncall.CallFuncCapture(greet, `[""]`)
+		assert.EqualError(t, err, `name required`)
+
+		_, err = jsoncall.CallFuncCapture(greet, `[]`)
+		assert.EqualError(t, err, `Too few arguments: expected 1, got 0`)
+	})
+
+	t.Run("should not write into the caller's options", func(t *testing.T) {
+		options := make([]jsoncall.Option, 1, 2)
+		options[0] = jsoncall.WithRejectNull()
+
+		var wg sync.WaitGroup
+		for _, name := range []string{"Tobi", "Loki", "Jane"} {
+			wg.Add(1)
+			go func(name string) {
+				defer wg.Done()
+				b, err := jsoncall.CallFuncCapture(greet, `["`+name+`"]`, options...)
+				assert.NoError(t, err)
+				assert.Equal(t, "Hello "+name, string(b))
+			}(name)
+		}
+		wg.Wait()
+
+		assert.Len(t, options, 1)
+		assert.True(t, options[:2][1] == nil)
+	})
+
+	t.Run("should only inject exactly io.Writer", func(t *testing.T) {
+		fn := func(w *strings.Builder) {}
+		_, err := jsoncall.CallFuncCapture(fn, `[]`)
+		assert.EqualError(t, err, `Too few arguments: expected 1, got 0`)
+
+		b, err := jsoncall.CallFuncCapture(add, `[1, 2]`)
+		assert.NoError(t, err)
+		assert.Empty(t, b)
+	})
+}
+
+// Test calling of functions for their error only.
+func TestCallVoid(t *testing.T) {
+	t.Run("should return nil on success", func(t *testing.T) {
+		var called bool
+		fn := func(name string) error { called = true; return nil }
+		assert.NoError(t, jsoncall.CallVoid(fn, `["Tobi"]`))
+		assert.True(t, called)
+	})
+
+	t.Run("should support functions without results", func(t *testing.T) {
+		var name string
+		fn := func(v string) { name = v }
+		assert.NoError(t, jsoncall.CallVoid(fn, `["Tobi"]`))
+		assert.Equal(t, "Tobi", name)
+	})
+
+	t.Run("should return errors", func(t *testing.T) {
+		assert.EqualError(t, jsoncall.CallVoid(addPet, `["Tobi"]`), `error adding pet`)
+		assert.EqualError(t, jsoncall.CallVoid(addPet, `[]`), `Too few arguments: expected 1, got 0`)
+	})
+}
+
+// Test calling functions with Go values.
+func TestCallFuncValues(t *testing.T) {
+	t.Run("should invoke with the given values", func(t *testing.T) {
+		v, err := jsoncall.CallFuncValues(add, []interface{}{1, 2})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, v[0].Interface())
+
+		v, err = jsoncall.CallFuncValues(func() string { return "Hello" }, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "Hello", v[0].Interface())
+	})
+
+	t.Run("should inject the context", func(t *testing.T) {
+		type key struct{}
+		ctx := context.WithValue(context.Background(), key{}, "Tobi")
+		fn := func(ctx context.Context, greeting string) string { return greeting + " " + ctx.Value(key{}).(string) }
+
+		v, err := jsoncall.CallFuncValues(fn, []interface{}{"Hello"}, jsoncall.WithContext(ctx))
+		assert.NoError(t, err)
+		assert.Equal(t, "Hello Tobi", v[0].Interface())
+	})
+
+	t.Run("should inject values", func(t *testing.T) {
+		fn := func(prefix string, name string) string { return prefix + name }
+		v, err := jsoncall.CallFuncValues(fn, []interface{}{"Tobi"}, jsoncall.WithInjected(reflect.ValueOf("Mr. ")))
+		assert.NoError(t, err)
+		assert.Equal(t, "Mr. Tobi", v[0].Interface())
+	})
+
+	t.Run("should handle error results", func(t *testing.T) {
+		_, err := jsoncall.CallFuncValues(addPet, []interface{}{"Tobi"})
 		assert.EqualError(t, err, `error adding pet`)
+
+		v, err := jsoncall.CallFuncValues(addUserContext, []interface{}{User{Name: "Tobi"}})
+		assert.NoError(t, err)
+		assert.Len(t, v, 1)
+	})
+
+	t.Run("should support nil, interfaces and variadic values", func(t *testing.T) {
+		fn := func(u *User, v interface{}, tags ...string) (bool, interface{}, []string) { return u == nil, v, tags }
+
+		v, err := jsoncall.CallFuncValues(fn, []interface{}{nil, 5, "a", "b"})
+		assert.NoError(t, err)
+		assert.Equal(t, true, v[0].Interface())
+		assert.Equal(t, 5, v[1].Interface())
+		assert.Equal(t, []string{"a", "b"}, v[2].Interface())
+
+		v, err = jsoncall.CallFuncValues(fn, []interface{}{&User{}, nil})
+		assert.NoError(t, err)
+		assert.Equal(t, false, v[0].Interface())
+		assert.Nil(t, v[1].Interface())
+		assert.Nil(t, v[2].Interface())
+	})
+
+	t.Run("should error on incorrect types", func(t *testing.T) {
+		_, err := jsoncall.CallFuncValues(add, []interface{}{1, "2"})
+		assert.EqualError(t, err, `Argument 1: Incorrect type string, expected int`)
+		assert.True(t, errors.Is(err, jsoncall.ErrArgumentType))
+		assert.Equal(t, 400, jsoncall.ErrorCode(err))
+
+		var e *jsoncall.ArgumentTypeError
+		assert.True(t, errors.As(err, &e))
+		assert.Equal(t, reflect.TypeOf(""), e.Type)
+		assert.Equal(t, reflect.TypeOf(0), e.Expected)
+
+		_, err = jsoncall.CallFuncValues(add, []interface{}{nil, 2})
+		assert.EqualError(t, err, `Argument 0: Incorrect type nil, expected int`)
+
+		_, err = jsoncall.CallFuncValues(sum, []interface{}{1, 2.5})
+		assert.EqualError(t, err, `Argument 1: Incorrect type float64, expected int`)
+	})
+
+	t.Run("should error on arity", func(t *testing.T) {
+		_, err := jsoncall.CallFuncValues(add, []interface{}{1})
+		assert.EqualError(t, err, `Too few arguments: expected 2, got 1`)
+
+		_, err = jsoncall.CallFuncValues(add, []interface{}{1, 2, 3})
+		assert.EqualError(t, err, `Too many arguments: expected 2, got 3`)
+	})
+
+	t.Run("should error when not a function", func(t *testing.T) {
+		_, err := jsoncall.CallFuncValues(5, nil)
+		assert.Equal(t, jsoncall.ErrNotFunction, err)
+	})
+}
+
+// Test calling functions with a result pointer.
+func TestCallFuncInto(t *testing.T) {
+	t.Run("should assign the result", func(t *testing.T) {
+		var n int
+		assert.NoError(t, jsoncall.CallFuncInto(add, `[1, 2]`, &n))
+		assert.Equal(t, 3, n)
+
+		var v interface{}
+		assert.NoError(t, jsoncall.CallFuncInto(add, `[2, 2]`, &v))
+		assert.Equal(t, 4, v)
+	})
+
+	t.Run("should ignore error results", func(t *testing.T) {
+		fn := func(name string) (*User, error) { return &User{Name: name}, nil }
+
+		var u *User
+		assert.NoError(t, jsoncall.CallFuncInto(fn, `["Tobi"]`, &u))
+		assert.Equal(t, "Tobi", u.Name)
+	})
+
+	t.Run("should assign interface results by their dynamic type", func(t *testing.T) {
+		fn := func(ok bool) interface{} {
+			if ok {
+				return "yes"
+			}
+			return nil
+		}
+
+		s := "unset"
+		assert.NoError(t, jsoncall.CallFuncInto(fn, `[true]`, &s))
+		assert.Equal(t, "yes", s)
+
+		assert.NoError(t, jsoncall.CallFuncInto(fn, `[false]`, &s))
+		assert.Equal(t, "", s)
+
+		var n int
+		assert.Equal(t, jsoncall.ErrResultType, jsoncall.CallFuncInto(fn, `[true]`, &n))
+	})
+
+	t.Run("should error without invoking on mismatched results", func(t *testing.T) {
+		var called bool
+		fn := func() (int, int) { called = true; return 1, 2 }
+
+		var n int
+		assert.Equal(t, jsoncall.ErrResultCount, jsoncall.CallFuncInto(fn, `[]`, &n))
+		assert.Equal(t, jsoncall.ErrResultCount, jsoncall.CallFuncInto(addPet, `["Tobi"]`, &n))
+
+		var s string
+		assert.Equal(t, jsoncall.ErrResultType, jsoncall.CallFuncInto(add, `[1, 2]`, &s))
+		assert.Equal(t, jsoncall.ErrResultType, jsoncall.CallFuncInto(add, `[1, 2]`, s))
+		assert.Equal(t, jsoncall.ErrResultType, jsoncall.CallFuncInto(add, `[1, 2]`, nil))
+		assert.Equal(t, jsoncall.ErrNotFunction, jsoncall.CallFuncInto(5, `[]`, &n))
+		assert.False(t, called)
+	})
+
+	t.Run("should return errors", func(t *testing.T) {
+		fn := func(name string) (*User, error) { return nil, errors.New("boom") }
+
+		var u *User
+		assert.EqualError(t, jsoncall.CallFuncInto(fn, `["Tobi"]`, &u), `boom`)
+		assert.EqualError(t, jsoncall.CallFuncInto(add, `[1]`, new(int)), `Too few arguments: expected 2, got 1`)
+		assert.Nil(t, u)
 	})
 }
 
 // Test calling of methods.
 func TestCallMethod(t *testing.T) {
+	t.Run("should report the method name via WithObserver", func(t *testing.T) {
+		var name string
+		s := &mathService{}
+		m, _ := reflect.TypeOf(s).MethodByName("Sum")
+		_, err := jsoncall.CallMethod(s, m, `[[1,2]]`, jsoncall.WithObserver(func(n string, dur time.Duration, err error) {
+			name = n
+		}))
+		assert.NoError(t, err)
+		assert.Equal(t, "Sum", name)
+	})
+
+	t.Run("should error on unexported methods", func(t *testing.T) {
+		s := &mathService{}
+		m, _ := reflect.TypeOf(s).MethodByName("Sum")
+		m.Name = "sum"
+		m.PkgPath = "github.com/tj/go-jsoncall_test"
+
+		_, err := jsoncall.CallMethod(s, m, `[[1,2]]`)
+		assert.EqualError(t, err, `Method must be exported`)
+
+		_, err = jsoncall.CallMethodArgs(s, m, nil)
+		assert.Equal(t, jsoncall.ErrUnexportedMethod, err)
+	})
+
 	t.Run("should support returning a value", func(t *testing.T) {
 		s := &mathService{}
 		m, _ := reflect.TypeOf(s).MethodByName("Sum")
@@ -239,12 +2973,47 @@ func TestCallMethod(t *testing.T) {
 // Benchmark argument reflection.
 func BenchmarkArguments(b *testing.B) {
 	b.SetBytes(1)
+	b.ReportAllocs()
 	t := reflect.TypeOf(addUsers)
 	for i := 0; i < b.N; i++ {
 		jsoncall.ArgumentsOfFunc(t, `[[{ "name": "Tobi" }, { "name": "Loki" }]]`)
 	}
 }
 
+// Benchmark argument reflection for large arrays.
+func BenchmarkArgumentsLarge(b *testing.B) {
+	fn := func(a, b, c []int) {}
+	t := reflect.TypeOf(fn)
+	nums := "[" + strings.TrimSuffix(strings.Repeat("1,", 10000), ",") + "]"
+	args := "[" + nums + "," + nums + "," + nums + "]"
+
+	b.Run("default", func(b *testing.B) {
+		b.SetBytes(int64(len(args)))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			jsoncall.ArgumentsOfFunc(t, args)
+		}
+	})
+
+	b.Run("streaming", func(b *testing.B) {
+		b.SetBytes(int64(len(args)))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			jsoncall.ArgumentsOfFunc(t, args, jsoncall.WithStreamingDecode())
+		}
+	})
+}
+
+// Benchmark argument reflection for functions without params.
+func BenchmarkArgumentsNoParams(b *testing.B) {
+	b.SetBytes(1)
+	b.ReportAllocs()
+	t := reflect.TypeOf(func() {})
+	for i := 0; i < b.N; i++ {
+		jsoncall.ArgumentsOfFunc(t, `[]`)
+	}
+}
+
 // Benchmark function calling.
 func BenchmarkCallFunc(b *testing.B) {
 	b.SetBytes(1)
@@ -0,0 +1,33 @@
+// Package jsoncalltest provides utilities for testing functions called with jsoncall.
+package jsoncalltest
+
+import (
+	"reflect"
+	"testing"
+
+	jsoncall "github.com/tj/go-jsoncall"
+)
+
+// MustCall invokes fn with arguments derived from a json string as
+// jsoncall.CallFunc does, failing the test on error. The results are returned
+// as interface values, including nil error values.
+func MustCall(t testing.TB, fn interface{}, args string, options ...jsoncall.Option) []interface{} {
+	t.Helper()
+
+	values, err := jsoncall.CallFunc(fn, args, options...)
+	if err != nil {
+		t.Fatalf("calling with %s: %s", args, err)
+		return nil
+	}
+
+	return interfaces(values)
+}
+
+// interfaces returns the interface values of values.
+func interfaces(values []reflect.Value) []interface{} {
+	v := make([]interface{}, len(values))
+	for i, value := range values {
+		v[i] = value.Interface()
+	}
+	return v
+}
@@ -0,0 +1,53 @@
+package jsoncalltest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/tj/assert"
+	jsoncall "github.com/tj/go-jsoncall"
+	"github.com/tj/go-jsoncall/jsoncalltest"
+)
+
+// recorder is a testing.TB which records failures.
+type recorder struct {
+	testing.TB
+	failure string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Fatalf(format string, args ...interface{}) {
+	r.failure = fmt.Sprintf(format, args...)
+}
+
+// Test calling functions which must succeed.
+func TestMustCall(t *testing.T) {
+	t.Run("should return the results", func(t *testing.T) {
+		add := func(a, b int) int { return a + b }
+		assert.Equal(t, []interface{}{3}, jsoncalltest.MustCall(t, add, `[1, 2]`))
+	})
+
+	t.Run("should return nil errors", func(t *testing.T) {
+		fn := func(ctx context.Context, name string) (string, error) { return "Hello " + name, nil }
+		assert.Equal(t, []interface{}{"Hello Tobi", nil}, jsoncalltest.MustCall(t, fn, `["Tobi"]`))
+	})
+
+	t.Run("should pass options", func(t *testing.T) {
+		fn := func(flag bool) bool { return flag }
+		assert.Equal(t, []interface{}{true}, jsoncalltest.MustCall(t, fn, `[1]`, jsoncall.WithCoerceBool()))
+	})
+
+	t.Run("should fail the test on error", func(t *testing.T) {
+		r := &recorder{TB: t}
+		fn := func(name string) error { return errors.New("boom") }
+		assert.Nil(t, jsoncalltest.MustCall(r, fn, `["Tobi"]`))
+		assert.Equal(t, `calling with ["Tobi"]: boom`, r.failure)
+
+		r = &recorder{TB: t}
+		jsoncalltest.MustCall(r, fn, `[]`)
+		assert.Equal(t, `calling with []: Too few arguments: expected 1, got 0`, r.failure)
+	})
+}
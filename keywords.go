@@ -0,0 +1,83 @@
+package jsoncall
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// ErrKeywordConflict is returned when a keyword names a parameter already given positionally.
+var ErrKeywordConflict error = newCallError(http.StatusBadRequest, "keyword_conflict", "Keyword argument conflicts with a positional argument")
+
+// KeywordError is returned when a trailing keyword names a parameter which was
+// already given positionally. It unwraps to ErrKeywordConflict.
+type KeywordError struct {
+	Name string
+}
+
+// Error implementation.
+func (e *KeywordError) Error() string {
+	return fmt.Sprintf("Keyword %q was already given positionally", e.Name)
+}
+
+// Unwrap returns ErrKeywordConflict.
+func (e *KeywordError) Unwrap() error {
+	return ErrKeywordConflict
+}
+
+// WithTrailingKeywords names the parameters in order, excluding the receiver
+// and any context, so that Python-style calls such as [1, 2, {"verbose": true}]
+// may fill leading parameters positionally and later ones by name. A trailing
+// object is treated as keywords only when every key is one of the names,
+// otherwise it is decoded positionally as usual. Parameters skipped between
+// positional and keyword arguments decode from null.
+func WithTrailingKeywords(names ...string) Option {
+	return func(v *config) {
+		v.keywords = names
+	}
+}
+
+// trailingKeywords returns the params with a trailing object of keywords
+// replaced by positional params.
+func (c *config) trailingKeywords(params []json.RawMessage) ([]json.RawMessage, error) {
+	if len(params) == 0 {
+		return params, nil
+	}
+
+	var kw map[string]json.RawMessage
+	if err := json.Unmarshal(params[len(params)-1], &kw); err != nil || len(kw) == 0 {
+		return params, nil
+	}
+
+	index := make(map[string]int, len(c.keywords))
+	for i, name := range c.keywords {
+		index[name] = i
+	}
+
+	for k := range kw {
+		if _, ok := index[k]; !ok {
+			return params, nil
+		}
+	}
+
+	positional := params[:len(params)-1]
+	out := append([]json.RawMessage(nil), positional...)
+
+	for i, name := range c.keywords {
+		v, ok := kw[name]
+		if !ok {
+			continue
+		}
+
+		if i < len(positional) {
+			return nil, &ArgumentError{Index: i, Err: &KeywordError{Name: name}}
+		}
+
+		for len(out) <= i {
+			out = append(out, json.RawMessage("null"))
+		}
+		out[i] = v
+	}
+
+	return out, nil
+}
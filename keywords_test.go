@@ -0,0 +1,60 @@
+package jsoncall_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/tj/assert"
+	jsoncall "github.com/tj/go-jsoncall"
+)
+
+// Test mixed positional and keyword arguments.
+func TestWithTrailingKeywords(t *testing.T) {
+	fn := func(ctx context.Context, a, b int, verbose bool, label string) {}
+	keywords := jsoncall.WithTrailingKeywords("a", "b", "verbose", "label")
+
+	t.Run("should fill later params by name", func(t *testing.T) {
+		args, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[1, 2, { "label": "x", "verbose": true }]`, keywords)
+		assert.NoError(t, err)
+		assert.Len(t, args, 5)
+		assert.Equal(t, 1, args[1].Interface())
+		assert.Equal(t, 2, args[2].Interface())
+		assert.Equal(t, true, args[3].Interface())
+		assert.Equal(t, "x", args[4].Interface())
+	})
+
+	t.Run("should decode skipped params from null", func(t *testing.T) {
+		args, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[1, { "label": "x" }]`, keywords)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, args[2].Interface())
+		assert.Equal(t, false, args[3].Interface())
+		assert.Equal(t, "x", args[4].Interface())
+	})
+
+	t.Run("should support positional arguments alone", func(t *testing.T) {
+		args, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[1, 2, true, "x"]`, keywords)
+		assert.NoError(t, err)
+		assert.Equal(t, "x", args[4].Interface())
+	})
+
+	t.Run("should error when too few arguments are given", func(t *testing.T) {
+		_, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[1, { "verbose": true }]`, keywords)
+		assert.EqualError(t, err, `Too few arguments: expected 4, got 3`)
+	})
+
+	t.Run("should error when a keyword was given positionally", func(t *testing.T) {
+		_, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[1, 2, { "b": 3, "label": "x" }]`, keywords)
+		assert.EqualError(t, err, `Argument 1: Keyword "b" was already given positionally`)
+		assert.True(t, errors.Is(err, jsoncall.ErrKeywordConflict))
+		assert.Equal(t, "keyword_conflict", jsoncall.ErrorCategory(err))
+	})
+
+	t.Run("should decode objects with other keys positionally", func(t *testing.T) {
+		fn := func(id int, u User) {}
+		args, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[1, { "name": "Tobi" }]`, jsoncall.WithTrailingKeywords("id", "user"))
+		assert.NoError(t, err)
+		assert.Equal(t, User{Name: "Tobi"}, args[1].Interface())
+	})
+}
@@ -0,0 +1,61 @@
+package jsoncall
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// maxLineLength is the maximum length of a line read by CallEachLine.
+const maxLineLength = 16 << 20
+
+// LineError is an error relating to the call of the given line, numbered
+// from 1, when calling a function for each line of newline-delimited JSON.
+type LineError struct {
+	Line int
+	Err  error
+}
+
+// Error implementation.
+func (e *LineError) Error() string {
+	return fmt.Sprintf("Line %d: %s", e.Line, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// CallEachLine invokes a function once for each line of newline-delimited
+// JSON read from r, each line holding the params of a call, for example to
+// replay recorded calls. Blank lines are skipped. The results hold an entry
+// for each call in order, which is empty for calls which fail, and the
+// errors of failed calls are collected as *LineError rather than stopping at
+// the first. An error reading r stops reading and is returned last.
+func CallEachLine(fn interface{}, r io.Reader, options ...Option) ([]Results, []error) {
+	var results []Results
+	var errs []error
+
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, maxLineLength)
+
+	for line := 1; s.Scan(); line++ {
+		args := s.Text()
+		if strings.TrimSpace(args) == "" {
+			continue
+		}
+
+		res, err := CallFuncResults(fn, args, options...)
+		if err != nil {
+			errs = append(errs, &LineError{Line: line, Err: err})
+		}
+		results = append(results, res)
+	}
+
+	if err := s.Err(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return results, errs
+}
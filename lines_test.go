@@ -0,0 +1,72 @@
+package jsoncall_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/tj/assert"
+	jsoncall "github.com/tj/go-jsoncall"
+)
+
+// Test calling a function for each line.
+func TestCallEachLine(t *testing.T) {
+	t.Run("should call the function once per line", func(t *testing.T) {
+		r := strings.NewReader("[1, 2]\n[3, 4]\r\n\n  \n[5, 6]")
+		results, errs := jsoncall.CallEachLine(add, r)
+		assert.Empty(t, errs)
+		assert.Len(t, results, 3)
+		assert.Equal(t, 3, results[0].First())
+		assert.Equal(t, 7, results[1].First())
+		assert.Equal(t, 11, results[2].First())
+	})
+
+	t.Run("should collect errors rather than stopping", func(t *testing.T) {
+		r := strings.NewReader("[1, 2]\n[1]\n\n[1, \"2\"]\n[1, 2\n[2, 2]\n")
+		results, errs := jsoncall.CallEachLine(add, r)
+		assert.Len(t, results, 5)
+		assert.Equal(t, 3, results[0].First())
+		assert.Equal(t, 0, results[1].Len())
+		assert.Equal(t, 1, results[1].ExpectedLen())
+		assert.Equal(t, 4, results[4].First())
+
+		assert.Len(t, errs, 3)
+		assert.EqualError(t, errs[0], `Line 2: Too few arguments: expected 2, got 1`)
+		assert.EqualError(t, errs[1], `Line 4: Incorrect type string, expected number`)
+		assert.EqualError(t, errs[2], `Line 5: Invalid JSON`)
+
+		var e *jsoncall.LineError
+		assert.True(t, errors.As(errs[2], &e))
+		assert.Equal(t, 5, e.Line)
+		assert.True(t, errors.Is(errs[2], jsoncall.ErrInvalidJSON))
+		assert.Equal(t, 400, jsoncall.ErrorCode(errs[2]))
+	})
+
+	t.Run("should collect errors returned by the function", func(t *testing.T) {
+		results, errs := jsoncall.CallEachLine(addPet, strings.NewReader(`["Tobi"]`+"\n"+`["Loki"]`))
+		assert.Len(t, results, 2)
+		assert.Len(t, errs, 2)
+		assert.EqualError(t, errs[1], `Line 2: error adding pet`)
+	})
+
+	t.Run("should apply options to each call", func(t *testing.T) {
+		results, errs := jsoncall.CallEachLine(add, strings.NewReader("[1, 2, 3]\n"), jsoncall.WithIgnoreExtraArguments())
+		assert.Empty(t, errs)
+		assert.Equal(t, 3, results[0].First())
+	})
+
+	t.Run("should stop on read errors", func(t *testing.T) {
+		r := io.MultiReader(strings.NewReader("[1, 2]\n"), iotest.ErrReader(io.ErrUnexpectedEOF))
+		results, errs := jsoncall.CallEachLine(add, r)
+		assert.Len(t, results, 1)
+		assert.Equal(t, []error{io.ErrUnexpectedEOF}, errs)
+	})
+
+	t.Run("should return nothing for empty input", func(t *testing.T) {
+		results, errs := jsoncall.CallEachLine(add, strings.NewReader(""))
+		assert.Nil(t, results)
+		assert.Nil(t, errs)
+	})
+}
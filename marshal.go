@@ -0,0 +1,222 @@
+package jsoncall
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+)
+
+// WithResultKeyTransform sets a function used to rewrite object keys when
+// marshaling results, including the keys of nested objects.
+func WithResultKeyTransform(fn func(string) string) Option {
+	return func(v *config) {
+		v.resultKeyTransform = fn
+	}
+}
+
+// WithIndent sets the indentation used when marshaling results, for
+// human-facing output such as CLIs.
+func WithIndent(indent string) Option {
+	return func(v *config) {
+		v.indent = indent
+	}
+}
+
+// WithNilSlicesAsEmpty marshals nil slice and map results as [] and {}
+// rather than null, so that clients needn't handle both. Only the results
+// themselves, or the values they point to, are affected; nested fields are
+// marshaled as-is.
+func WithNilSlicesAsEmpty() Option {
+	return func(v *config) {
+		v.nilSlicesAsEmpty = true
+	}
+}
+
+// MarshalResults returns the JSON representation of the results of a call.
+// Error results are omitted, a single result is marshaled as-is, and multiple
+// results are marshaled as an array. Values are marshaled by encoding/json, so
+// struct tags such as omitempty and MarshalJSON methods are honored.
+func MarshalResults(values []reflect.Value, options ...Option) ([]byte, error) {
+	c := newConfig(options)
+
+	var results []interface{}
+	for _, v := range values {
+		if isError(v.Type()) {
+			continue
+		}
+		results = append(results, c.result(v))
+	}
+
+	var value interface{} = results
+	switch len(results) {
+	case 0:
+		value = nil
+	case 1:
+		value = results[0]
+	}
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.resultKeyTransform != nil {
+		b, err = transformKeys(b, c.resultKeyTransform)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if c.indent != "" {
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, b, "", c.indent); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	return b, nil
+}
+
+// result returns the value to marshal for a result.
+func (c *config) result(v reflect.Value) interface{} {
+	if c.allowNonFiniteFloats && isFloat(v.Type()) {
+		f := v
+		for f.Kind() == reflect.Ptr && !f.IsNil() {
+			f = f.Elem()
+		}
+
+		if f.Kind() != reflect.Ptr {
+			if s, ok := nonFiniteString(f.Float()); ok {
+				return s
+			}
+		}
+	}
+
+	if c.nilSlicesAsEmpty {
+		if e, ok := emptyValue(v); ok {
+			return e
+		}
+	}
+
+	return v.Interface()
+}
+
+// emptyValue returns an empty slice or map when v is, or points to, a nil
+// slice or map.
+func emptyValue(v reflect.Value) (interface{}, bool) {
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	switch {
+	case v.Kind() == reflect.Slice && v.IsNil():
+		return reflect.MakeSlice(v.Type(), 0, 0).Interface(), true
+	case v.Kind() == reflect.Map && v.IsNil():
+		return reflect.MakeMap(v.Type()).Interface(), true
+	default:
+		return nil, false
+	}
+}
+
+// keyFunc returns the key of an object decoding into t rewritten, along with
+// the type its value decodes into.
+type keyFunc func(t reflect.Type, key string) (string, reflect.Type)
+
+// transformKeys rewrites the object keys of a JSON value, preserving order.
+func transformKeys(data []byte, fn func(string) string) ([]byte, error) {
+	return rewriteKeys(data, nil, func(_ reflect.Type, key string) (string, reflect.Type) {
+		return fn(key), nil
+	})
+}
+
+// transformStructKeys rewrites the object keys of a JSON value decoding into
+// t, preserving order, only rewriting the keys of objects decoding into
+// structs.
+func transformStructKeys(data []byte, t reflect.Type, fn func(string) string) ([]byte, error) {
+	return rewriteKeys(data, t, func(t reflect.Type, key string) (string, reflect.Type) {
+		if isStructObject(t) {
+			key = fn(key)
+		}
+		return key, valueType(t, key)
+	})
+}
+
+// rewriteKeys rewrites the object keys of a JSON value decoding into t.
+func rewriteKeys(data []byte, t reflect.Type, fn keyFunc) ([]byte, error) {
+	var buf bytes.Buffer
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	if err := transformValue(dec, &buf, t, fn); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// transformValue writes the next value from dec to buf, rewriting object keys.
+func transformValue(dec *json.Decoder, buf *bytes.Buffer, t reflect.Type, fn keyFunc) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	d, ok := tok.(json.Delim)
+	if !ok {
+		b, err := json.Marshal(tok)
+		if err != nil {
+			return err
+		}
+		buf.Write(b)
+		return nil
+	}
+
+	object := d == '{'
+	if object {
+		buf.WriteByte('{')
+	} else {
+		buf.WriteByte('[')
+	}
+
+	for i := 0; dec.More(); i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		vt := elemType(t)
+		if object {
+			tok, err := dec.Token()
+			if err != nil {
+				return err
+			}
+
+			var key string
+			key, vt = fn(t, tok.(string))
+
+			b, err := json.Marshal(key)
+			if err != nil {
+				return err
+			}
+			buf.Write(b)
+			buf.WriteByte(':')
+		}
+
+		if err := transformValue(dec, buf, vt, fn); err != nil {
+			return err
+		}
+	}
+
+	// closing delimiter
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+
+	if object {
+		buf.WriteByte('}')
+	} else {
+		buf.WriteByte(']')
+	}
+
+	return nil
+}
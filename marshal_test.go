@@ -0,0 +1,173 @@
+package jsoncall_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/tj/assert"
+	jsoncall "github.com/tj/go-jsoncall"
+)
+
+// Temperature is a result with a custom marshaler.
+type Temperature float64
+
+// MarshalJSON implementation.
+func (t Temperature) MarshalJSON() ([]byte, error) {
+	return []byte(fmt.Sprintf(`"%.1f°C"`, float64(t))), nil
+}
+
+// snakeCase converts camelCase to snake_case.
+func snakeCase(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+type Profile struct {
+	FirstName string  `json:"firstName"`
+	LastName  string  `json:"lastName"`
+	Address   Address `json:"homeAddress"`
+}
+
+type Address struct {
+	StreetName string `json:"streetName"`
+}
+
+// Test marshaling of results.
+func TestMarshalResults(t *testing.T) {
+	t.Run("should marshal no results as null", func(t *testing.T) {
+		b, err := jsoncall.MarshalResults(nil)
+		assert.NoError(t, err)
+		assert.Equal(t, `null`, string(b))
+	})
+
+	t.Run("should marshal a single result", func(t *testing.T) {
+		b, err := jsoncall.MarshalResults([]reflect.Value{reflect.ValueOf(User{Name: "Tobi"})})
+		assert.NoError(t, err)
+		assert.Equal(t, `{"name":"Tobi","email":""}`, string(b))
+	})
+
+	t.Run("should marshal multiple results as an array", func(t *testing.T) {
+		minmax := func(a, b int) (int, int, error) { return a, b, nil }
+		v, err := jsoncall.CallFunc(minmax, `[1, 2]`)
+		assert.NoError(t, err)
+
+		b, err := jsoncall.MarshalResults(v)
+		assert.NoError(t, err)
+		assert.Equal(t, `[1,2]`, string(b))
+	})
+
+	t.Run("should honor omitempty", func(t *testing.T) {
+		type user struct {
+			Name  string `json:"name"`
+			Email string `json:"email,omitempty"`
+		}
+
+		b, err := jsoncall.MarshalResults([]reflect.Value{reflect.ValueOf(user{Name: "Tobi"})})
+		assert.NoError(t, err)
+		assert.Equal(t, `{"name":"Tobi"}`, string(b))
+	})
+
+	t.Run("should honor custom marshalers", func(t *testing.T) {
+		v := []reflect.Value{reflect.ValueOf(Temperature(21.5)), reflect.ValueOf([]Temperature{1, 2})}
+		b, err := jsoncall.MarshalResults(v)
+		assert.NoError(t, err)
+		assert.Equal(t, `["21.5°C",["1.0°C","2.0°C"]]`, string(b))
+	})
+
+	t.Run("should not wrap a single slice result", func(t *testing.T) {
+		b, err := jsoncall.MarshalResults([]reflect.Value{reflect.ValueOf([]int{1, 2})})
+		assert.NoError(t, err)
+		assert.Equal(t, `[1,2]`, string(b))
+	})
+
+	t.Run("should indent via WithIndent", func(t *testing.T) {
+		v := []reflect.Value{reflect.ValueOf(User{Name: "Tobi"})}
+		b, err := jsoncall.MarshalResults(v, jsoncall.WithIndent("  "))
+		assert.NoError(t, err)
+		assert.Equal(t, "{\n  \"name\": \"Tobi\",\n  \"email\": \"\"\n}", string(b))
+
+		b, err = jsoncall.MarshalResults(v, jsoncall.WithIndent("  "), jsoncall.WithResultKeyTransform(strings.ToUpper))
+		assert.NoError(t, err)
+		assert.Equal(t, "{\n  \"NAME\": \"Tobi\",\n  \"EMAIL\": \"\"\n}", string(b))
+	})
+
+	t.Run("should omit error results", func(t *testing.T) {
+		v := []reflect.Value{reflect.ValueOf(1), reflect.ValueOf(errors.New("boom"))}
+		v[1] = v[1].Convert(reflect.TypeOf((*error)(nil)).Elem())
+		b, err := jsoncall.MarshalResults(v)
+		assert.NoError(t, err)
+		assert.Equal(t, `1`, string(b))
+	})
+
+	t.Run("should transform keys via WithResultKeyTransform", func(t *testing.T) {
+		p := Profile{FirstName: "Tobi", LastName: "Ferret", Address: Address{StreetName: "Main"}}
+		b, err := jsoncall.MarshalResults([]reflect.Value{reflect.ValueOf(p)}, jsoncall.WithResultKeyTransform(snakeCase))
+		assert.NoError(t, err)
+		assert.Equal(t, `{"first_name":"Tobi","last_name":"Ferret","home_address":{"street_name":"Main"}}`, string(b))
+	})
+
+	t.Run("should transform keys within arrays", func(t *testing.T) {
+		p := []Profile{{FirstName: "Tobi"}}
+		b, err := jsoncall.MarshalResults([]reflect.Value{reflect.ValueOf(p)}, jsoncall.WithResultKeyTransform(snakeCase))
+		assert.NoError(t, err)
+		assert.Equal(t, `[{"first_name":"Tobi","last_name":"","home_address":{"street_name":""}}]`, string(b))
+	})
+
+	t.Run("should marshal nil slices and maps as empty via WithNilSlicesAsEmpty", func(t *testing.T) {
+		var users []User
+		var tags map[string]string
+
+		b, err := jsoncall.MarshalResults([]reflect.Value{reflect.ValueOf(users)})
+		assert.NoError(t, err)
+		assert.Equal(t, `null`, string(b))
+
+		b, err = jsoncall.MarshalResults([]reflect.Value{reflect.ValueOf(users)}, jsoncall.WithNilSlicesAsEmpty())
+		assert.NoError(t, err)
+		assert.Equal(t, `[]`, string(b))
+
+		b, err = jsoncall.MarshalResults([]reflect.Value{reflect.ValueOf(tags)})
+		assert.NoError(t, err)
+		assert.Equal(t, `null`, string(b))
+
+		b, err = jsoncall.MarshalResults([]reflect.Value{reflect.ValueOf(tags), reflect.ValueOf(&users)}, jsoncall.WithNilSlicesAsEmpty())
+		assert.NoError(t, err)
+		assert.Equal(t, `[{},[]]`, string(b))
+	})
+
+	t.Run("should marshal empty slices and maps as-is", func(t *testing.T) {
+		v := []reflect.Value{reflect.ValueOf([]User{}), reflect.ValueOf(map[string]string{})}
+		b, err := jsoncall.MarshalResults(v)
+		assert.NoError(t, err)
+		assert.Equal(t, `[[],{}]`, string(b))
+
+		b, err = jsoncall.MarshalResults(v, jsoncall.WithNilSlicesAsEmpty())
+		assert.NoError(t, err)
+		assert.Equal(t, `[[],{}]`, string(b))
+	})
+
+	t.Run("should not affect nil pointers or nested fields", func(t *testing.T) {
+		var u *User
+		var users *[]User
+		type page struct {
+			Items []User `json:"items"`
+		}
+
+		v := []reflect.Value{reflect.ValueOf(u), reflect.ValueOf(users), reflect.ValueOf(page{})}
+		b, err := jsoncall.MarshalResults(v, jsoncall.WithNilSlicesAsEmpty())
+		assert.NoError(t, err)
+		assert.Equal(t, `[null,null,{"items":null}]`, string(b))
+	})
+}
@@ -0,0 +1,81 @@
+package jsoncall
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrMissingParams is returned when the params key is absent from the arguments object.
+var ErrMissingParams error = newCallError(http.StatusBadRequest, "missing_params", "Missing params")
+
+// ParamsKeyError is returned when the arguments are not an object containing
+// the params key. It unwraps to ErrMissingParams.
+type ParamsKeyError struct {
+	Key string
+}
+
+// Error implementation.
+func (e *ParamsKeyError) Error() string {
+	return fmt.Sprintf("Missing params key %q", e.Key)
+}
+
+// Unwrap returns ErrMissingParams.
+func (e *ParamsKeyError) Unwrap() error {
+	return ErrMissingParams
+}
+
+// WithParamsKey reads the params array from the given key of a top-level
+// object, such as "params" for {"method": "add", "params": [1, 2]}, which is
+// then processed as usual. Input which is not an object with the key returns
+// a *ParamsKeyError. Spans returned by ArgumentsOfFuncMeta remain relative to
+// the whole input.
+func WithParamsKey(key string) Option {
+	return func(v *config) {
+		v.paramsKey = key
+	}
+}
+
+// extractParams returns the value of the params key within s along with its
+// offset, or s itself when no params key is configured.
+func (c *config) extractParams(s string) (string, int, error) {
+	if c.paramsKey == "" {
+		return s, 0, nil
+	}
+
+	dec := json.NewDecoder(strings.NewReader(s))
+
+	tok, err := dec.Token()
+	if err != nil {
+		return "", 0, ErrInvalidJSON
+	}
+
+	if d, ok := tok.(json.Delim); !ok || d != '{' {
+		return "", 0, &ParamsKeyError{Key: c.paramsKey}
+	}
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return "", 0, ErrInvalidJSON
+		}
+
+		start := int(dec.InputOffset())
+
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return "", 0, ErrInvalidJSON
+		}
+
+		if tok == c.paramsKey {
+			// skip the colon and whitespace preceding the value
+			raw := s[start:dec.InputOffset()]
+			start += bytes.Index([]byte(raw), bytes.TrimSpace(value))
+			return s[start : start+len(bytes.TrimSpace(value))], start, nil
+		}
+	}
+
+	return "", 0, &ParamsKeyError{Key: c.paramsKey}
+}
@@ -0,0 +1,79 @@
+package jsoncall_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/tj/assert"
+	jsoncall "github.com/tj/go-jsoncall"
+)
+
+// Test reading params from a key of an envelope.
+func TestWithParamsKey(t *testing.T) {
+	key := jsoncall.WithParamsKey("params")
+
+	t.Run("should read params from the key", func(t *testing.T) {
+		v, err := jsoncall.CallFunc(add, `{ "method": "add", "params": [1, 2] }`, key)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, v[0].Interface())
+
+		v, err = jsoncall.CallFunc(add, `{"params":[3,4],"id":1}`, key, jsoncall.WithStreamingDecode())
+		assert.NoError(t, err)
+		assert.Equal(t, 7, v[0].Interface())
+
+		n, err := jsoncall.Call2(add, `{"params": [5, 6]}`, key)
+		assert.NoError(t, err)
+		assert.Equal(t, 11, n)
+	})
+
+	t.Run("should process params as usual", func(t *testing.T) {
+		_, err := jsoncall.CallFunc(add, `{"params": [1]}`, key)
+		assert.EqualError(t, err, `Too few arguments: expected 2, got 1`)
+
+		_, err = jsoncall.CallFunc(add, `{"params": 5}`, key)
+		assert.Equal(t, jsoncall.ErrExpectedArray, err)
+
+		_, err = jsoncall.CallFunc(func() {}, `{"params": []}`, key)
+		assert.NoError(t, err)
+	})
+
+	t.Run("should return spans relative to the input", func(t *testing.T) {
+		args := `{"id": 1, "params" :  [1, "5"]}`
+		_, spans, err := jsoncall.ArgumentsOfFuncMeta(reflect.TypeOf(add), args, key)
+		assert.EqualError(t, err, `Incorrect type string, expected number`)
+		assert.Equal(t, `1`, args[spans[0].Start:spans[0].End])
+		assert.Equal(t, `"5"`, args[spans[1].Start:spans[1].End])
+	})
+
+	t.Run("should error when the key is missing", func(t *testing.T) {
+		for _, args := range []string{`{"method": "add"}`, `[1, 2]`, ``, `{"param": [1, 2]}`} {
+			_, err := jsoncall.CallFunc(add, args, key)
+			assert.EqualError(t, err, `Missing params key "params"`, args)
+			assert.True(t, errors.Is(err, jsoncall.ErrMissingParams), args)
+			assert.Equal(t, "missing_params", jsoncall.ErrorCategory(err), args)
+
+			var e *jsoncall.ParamsKeyError
+			assert.True(t, errors.As(err, &e), args)
+			assert.Equal(t, "params", e.Key)
+		}
+	})
+
+	t.Run("should error on invalid json", func(t *testing.T) {
+		_, err := jsoncall.CallFunc(add, `{"method": }`, key)
+		assert.Equal(t, jsoncall.ErrInvalidJSON, err)
+	})
+
+	t.Run("should resolve registry overloads", func(t *testing.T) {
+		r := jsoncall.NewRegistry()
+		assert.NoError(t, r.Register("sum", func(a int) int { return a }))
+		assert.NoError(t, r.Register("sum", add))
+
+		v, err := r.Call("sum", `{"params": [1, 2]}`, key)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, v[0].Interface())
+
+		_, err = r.Call("sum", `{}`, key)
+		assert.True(t, errors.Is(err, jsoncall.ErrMissingParams))
+	})
+}
@@ -0,0 +1,156 @@
+package jsoncall
+
+import (
+	"encoding/json"
+	"reflect"
+	"sync"
+)
+
+// maxParamsBuffer is the largest input, in bytes, whose buffer is reused.
+const maxParamsBuffer = 64 << 10
+
+// paramsPool holds *paramsBuffer for parsing params arrays.
+var paramsPool = sync.Pool{
+	New: func() interface{} { return new(paramsBuffer) },
+}
+
+// paramsBuffer is reusable storage for parsing a params array, which is
+// discarded once its params are decoded. Only the input and the slice are
+// reused, each param being a copy which remains valid after the buffer is
+// released.
+type paramsBuffer struct {
+	data   []byte
+	params []json.RawMessage
+}
+
+// getParamsBuffer returns a buffer from the pool.
+func getParamsBuffer() *paramsBuffer {
+	return paramsPool.Get().(*paramsBuffer)
+}
+
+// putParamsBuffer zeroes the buffer's params, so that it holds no references,
+// and returns it to the pool unless its input was too large to keep.
+func putParamsBuffer(b *paramsBuffer) {
+	if cap(b.data) > maxParamsBuffer {
+		return
+	}
+
+	params := b.params[:cap(b.params)]
+	for i := range params {
+		params[i] = nil
+	}
+
+	paramsPool.Put(b)
+}
+
+// parse parses the json array of params as parseParams does.
+func (b *paramsBuffer) parse(s string) ([]json.RawMessage, error) {
+	b.data = append(b.data[:0], s...)
+	b.params = b.params[:0]
+
+	if err := paramsError(json.Unmarshal(b.data, &b.params)); err != nil {
+		return nil, err
+	}
+
+	return b.params, nil
+}
+
+// framePools holds a *sync.Pool of *argFrame for each function type.
+var framePools sync.Map
+
+// argFrame is reusable storage for the arguments of a function type. Frames
+// are only used when the arguments are discarded once the call completes, as
+// the function then holds copies of the values and never the storage itself.
+type argFrame struct {
+	slots []reflect.Value
+	args  []reflect.Value
+}
+
+// getFrame returns a frame for the given function type.
+func getFrame(t reflect.Type) *argFrame {
+	p, ok := framePools.Load(t)
+	if !ok {
+		p, _ = framePools.LoadOrStore(t, new(sync.Pool))
+	}
+
+	if f, ok := p.(*sync.Pool).Get().(*argFrame); ok {
+		return f
+	}
+
+	return &argFrame{
+		slots: make([]reflect.Value, t.NumIn()),
+		args:  make([]reflect.Value, 0, t.NumIn()),
+	}
+}
+
+// putFrame zeroes the frame, so that it holds no references, and returns it
+// to the pool of the given function type.
+func putFrame(t reflect.Type, f *argFrame) {
+	for _, s := range f.slots {
+		if s.IsValid() {
+			s.Elem().Set(reflect.Zero(s.Type().Elem()))
+		}
+	}
+
+	args := f.args[:cap(f.args)]
+	for i := range args {
+		args[i] = reflect.Value{}
+	}
+
+	if p, ok := framePools.Load(t); ok {
+		p.(*sync.Pool).Put(f)
+	}
+}
+
+// slot returns a pointer to zeroed storage for parameter p of type t.
+func (f *argFrame) slot(p int, t reflect.Type) reflect.Value {
+	if !f.slots[p].IsValid() {
+		f.slots[p] = reflect.New(t)
+	}
+	return f.slots[p]
+}
+
+// useFrame enables reusable storage for the arguments of t, which must be
+// released with releaseFrame once the call completes.
+func (c *config) useFrame(t reflect.Type) {
+	c.frameType = t
+}
+
+// releaseFrame returns the frame in use, if any, to its pool. Frames of
+// abandoned calls are left to the garbage collector, as the function may
+// still be running.
+func (c *config) releaseFrame() {
+	if c.frame != nil && !c.abandoned {
+		putFrame(c.frameType, c.frame)
+	}
+	c.frame = nil
+}
+
+// currentFrame returns the frame in use, or nil when frames are not enabled.
+func (c *config) currentFrame() *argFrame {
+	if c.frameType != nil && c.frame == nil {
+		c.frame = getFrame(c.frameType)
+	}
+	return c.frame
+}
+
+// newArg returns a pointer to storage for decoding parameter p of type t.
+func (c *config) newArg(p int, t reflect.Type) reflect.Value {
+	if f := c.currentFrame(); f != nil {
+		return f.slot(p, t)
+	}
+	return reflect.New(t)
+}
+
+// argsBuffer returns an empty slice for building the arguments of t.
+func (c *config) argsBuffer(t reflect.Type) []reflect.Value {
+	if f := c.currentFrame(); f != nil {
+		return f.args[:0]
+	}
+
+	if n := t.NumIn() - paramOffset(c.method); n > 1 {
+		return make([]reflect.Value, 0, n)
+	}
+
+	return nil
+}
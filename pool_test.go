@@ -0,0 +1,121 @@
+package jsoncall_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tj/assert"
+	jsoncall "github.com/tj/go-jsoncall"
+)
+
+// Test reuse of argument storage between calls.
+func TestArgumentReuse(t *testing.T) {
+	t.Run("should not share values between calls", func(t *testing.T) {
+		var users []*User
+		var tags []map[string]string
+		var names []User
+
+		fn := func(u *User, m map[string]string, v User) {
+			users = append(users, u)
+			tags = append(tags, m)
+			names = append(names, v)
+		}
+
+		_, err := jsoncall.CallFunc(fn, `[{ "name": "Tobi", "email": "tobi@apex.sh" }, { "a": "b" }, { "name": "Tobi", "email": "tobi@apex.sh" }]`)
+		assert.NoError(t, err)
+
+		_, err = jsoncall.CallFunc(fn, `[{ "name": "Loki" }, null, { "name": "Loki" }]`)
+		assert.NoError(t, err)
+
+		assert.Equal(t, &User{Name: "Tobi", Email: "tobi@apex.sh"}, users[0])
+		assert.Equal(t, &User{Name: "Loki"}, users[1])
+		assert.Equal(t, map[string]string{"a": "b"}, tags[0])
+		assert.Nil(t, tags[1])
+		assert.Equal(t, User{Name: "Loki"}, names[1])
+	})
+
+	t.Run("should not share values between method calls", func(t *testing.T) {
+		s := &stack[User]{}
+		m, _ := reflect.TypeOf(s).MethodByName("Push")
+
+		_, err := jsoncall.CallMethod(s, m, `[{ "name": "Tobi", "email": "tobi@apex.sh" }]`)
+		assert.NoError(t, err)
+
+		_, err = jsoncall.CallMethod(s, m, `[{ "name": "Loki" }]`)
+		assert.NoError(t, err)
+
+		assert.Equal(t, []User{{Name: "Tobi", Email: "tobi@apex.sh"}, {Name: "Loki"}}, s.items)
+	})
+
+	t.Run("should not share params between calls", func(t *testing.T) {
+		var raws []string
+		var data []json.RawMessage
+		hook := jsoncall.WithPreDecode(func(index int, raw json.RawMessage) error {
+			data = append(data, raw)
+			return nil
+		})
+
+		for _, args := range []string{`[{ "name": "Tobi" }]`, `[{ "name": "Loki" }]`, `[1]`} {
+			_, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(func(interface{}) {}), args, hook)
+			assert.NoError(t, err)
+			raws = append(raws, string(data[len(data)-1]))
+		}
+
+		for i, raw := range data {
+			assert.Equal(t, raws[i], string(raw))
+		}
+	})
+
+	t.Run("should support concurrent calls", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				v, err := jsoncall.CallFunc(add, fmt.Sprintf(`[%d, %d]`, i, i))
+				assert.NoError(t, err)
+				assert.Equal(t, i*2, v[0].Interface())
+			}(i)
+		}
+		wg.Wait()
+	})
+
+	t.Run("should not reuse the arguments of abandoned calls", func(t *testing.T) {
+		release := make(chan struct{})
+		done := make(chan User, 1)
+
+		fn := func(ctx context.Context, u User) {
+			<-release
+			done <- u
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+		defer cancel()
+
+		_, err := jsoncall.CallFunc(fn, `[{ "name": "Tobi" }]`, jsoncall.WithContext(ctx), jsoncall.WithAsyncCancellation())
+		assert.Equal(t, context.DeadlineExceeded, err)
+
+		_, err = jsoncall.CallFunc(func(ctx context.Context, u User) {}, `[{ "name": "Loki" }]`)
+		assert.NoError(t, err)
+
+		close(release)
+		assert.Equal(t, User{Name: "Tobi"}, <-done)
+	})
+}
+
+// Benchmark calling a method, which reuses argument storage between calls.
+func BenchmarkCallMethod(b *testing.B) {
+	s := &mathService{}
+	m, _ := reflect.TypeOf(s).MethodByName("Sum")
+
+	b.SetBytes(1)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		jsoncall.CallMethod(s, m, `[[1, 2, 3]]`)
+	}
+}
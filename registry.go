@@ -0,0 +1,314 @@
+package jsoncall
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"sync"
+)
+
+// ErrMethodNotFound is returned when calling a name which is not registered.
+var ErrMethodNotFound error = newCallError(http.StatusNotFound, "method_not_found", "Method not found")
+
+// ErrDuplicateArity is returned when registering a function under a name
+// which already has a function of the same arity.
+var ErrDuplicateArity error = newCallError(http.StatusInternalServerError, "duplicate_arity", "Function with the same arity already registered")
+
+// ErrAliasCycle is returned when an alias would resolve to itself.
+var ErrAliasCycle error = newCallError(http.StatusInternalServerError, "alias_cycle", "Alias would create a cycle")
+
+// ErrInvalidName is returned when registering a name which the registry's name
+// validator rejects.
+var ErrInvalidName error = newCallError(http.StatusInternalServerError, "invalid_name", "Invalid function name")
+
+// NameError is returned by ValidateName for invalid names. It unwraps to
+// ErrInvalidName.
+type NameError struct {
+	Name string
+}
+
+// Error implementation.
+func (e *NameError) Error() string {
+	return fmt.Sprintf("Invalid function name %q", e.Name)
+}
+
+// Unwrap returns ErrInvalidName.
+func (e *NameError) Unwrap() error {
+	return ErrInvalidName
+}
+
+// namePattern is the pattern of names accepted by ValidateName.
+var namePattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_.]*$`)
+
+// ValidateName is the default name validator of registries, returning a
+// *NameError unless name starts with a letter followed by letters, digits,
+// underscores or dots, such as "math.Sum".
+func ValidateName(name string) error {
+	if !namePattern.MatchString(name) {
+		return &NameError{Name: name}
+	}
+	return nil
+}
+
+// Registry is a set of named functions. Functions registered under the same
+// name are overloads, and are chosen by the number of arguments passed.
+type Registry struct {
+	mu           sync.RWMutex
+	funcs        map[string][]reflect.Value
+	aliases      map[string]string
+	validateName func(string) error
+}
+
+// NewRegistry returns a new registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		funcs:        make(map[string][]reflect.Value),
+		aliases:      make(map[string]string),
+		validateName: ValidateName,
+	}
+}
+
+// SetNameValidator sets the function used to validate names passed to
+// Register and Alias, which defaults to ValidateName. Names derived from
+// user input may be restricted further, and a nil function disables
+// validation.
+func (r *Registry) SetNameValidator(fn func(string) error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.validateName = fn
+}
+
+// Register adds a function under the given name. Multiple functions may be
+// registered under the same name provided their JSON arities differ. Functions
+// which CheckFunc rejects return its error, as do names which the name
+// validator rejects.
+func (r *Registry) Register(name string, fn interface{}) error {
+	if err := CheckFunc(fn); err != nil {
+		return err
+	}
+
+	v := reflect.ValueOf(fn)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.checkName(name); err != nil {
+		return err
+	}
+
+	arity := JSONArity(v.Type(), false)
+	for _, f := range r.funcs[name] {
+		if JSONArity(f.Type(), false) == arity {
+			return ErrDuplicateArity
+		}
+	}
+
+	r.funcs[name] = append(r.funcs[name], v)
+	return nil
+}
+
+// Alias makes calls to oldName invoke the functions of newName, such as when
+// renaming a method while keeping the old name working. Aliases resolve
+// transitively, so newName may itself be an alias or registered later, and
+// names with registered functions of their own take precedence. Aliases
+// which would resolve back to oldName return ErrAliasCycle, and oldName must
+// satisfy the name validator as registered names do.
+func (r *Registry) Alias(oldName, newName string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.checkName(oldName); err != nil {
+		return err
+	}
+
+	for name := newName; ; {
+		if name == oldName {
+			return ErrAliasCycle
+		}
+
+		next, ok := r.aliases[name]
+		if !ok {
+			break
+		}
+		name = next
+	}
+
+	r.aliases[oldName] = newName
+	return nil
+}
+
+// checkName returns the error of the name validator, if any. The lock must be held.
+func (r *Registry) checkName(name string) error {
+	if r.validateName == nil {
+		return nil
+	}
+	return r.validateName(name)
+}
+
+// RegisterMethods registers the exported methods of receiver under prefix
+// followed by the method name, such as "math." and "Sum" for "math.Sum",
+// mirroring how net/rpc registers receivers. Methods are bound to receiver,
+// and those which CheckFunc rejects are skipped.
+func RegisterMethods(reg *Registry, receiver interface{}, prefix string) error {
+	v := reflect.ValueOf(receiver)
+	if !v.IsValid() {
+		return ErrReceiverType
+	}
+
+	for i := 0; i < v.NumMethod(); i++ {
+		m := v.Type().Method(i)
+		fn := v.Method(i)
+
+		if CheckFunc(fn.Interface()) != nil {
+			continue
+		}
+
+		if err := reg.Register(prefix+m.Name, fn.Interface()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Call invokes the function registered under name with arguments derived
+// from a json string. When the name is overloaded, the function whose JSON
+// arity matches the number of arguments is invoked, falling back to a
+// variadic function which accepts them. Arguments are counted and arities
+// computed with the options of the call, such as injected values and
+// converters.
+func (r *Registry) Call(name string, args string, options ...Option) ([]reflect.Value, error) {
+	c := newConfig(options)
+
+	fn, err := r.lookup(name, args, c)
+	if err != nil {
+		return nil, err
+	}
+
+	c.name = name
+	return callPooled(fn, args, c)
+}
+
+// lookup returns the function for the given name and arguments.
+func (r *Registry) lookup(name, args string, c *config) (reflect.Value, error) {
+	r.mu.RLock()
+	funcs := r.funcs[name]
+	for len(funcs) == 0 && r.aliases[name] != "" {
+		name = r.aliases[name]
+		funcs = r.funcs[name]
+	}
+	r.mu.RUnlock()
+
+	switch len(funcs) {
+	case 0:
+		return reflect.Value{}, ErrMethodNotFound
+	case 1:
+		return funcs[0], nil
+	}
+
+	n, err := c.countParams(args)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	// prefer exact arities over variadic functions accepting n
+	var variadic reflect.Value
+	var accepted []int
+	for _, f := range funcs {
+		arity := c.arity(f.Type())
+		if arity == n {
+			return f, nil
+		}
+
+		if c.variadic(f.Type()) {
+			arity--
+			if n >= arity && !variadic.IsValid() {
+				variadic = f
+			}
+		}
+		if !containsInt(accepted, arity) {
+			accepted = append(accepted, arity)
+		}
+	}
+
+	if variadic.IsValid() {
+		return variadic, nil
+	}
+
+	sort.Ints(accepted)
+	return reflect.Value{}, &ArityError{Expected: nearest(accepted, n), Got: n, Accepted: accepted}
+}
+
+// CountParams returns the number of params in a json array string.
+func CountParams(args string) (int, error) {
+	if isEmptyArray(args) {
+		return 0, nil
+	}
+
+	params, err := parseParams(args)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(params), nil
+}
+
+// countParams returns the number of params in args as the call would see
+// them, after any conversion, params key or object argument handling.
+func (c *config) countParams(args string) (int, error) {
+	if c.hasCodec() {
+		params, err := c.splitParams(args)
+		if err != nil {
+			return 0, err
+		}
+		return len(params), nil
+	}
+
+	s, err := c.convert(args)
+	if err != nil {
+		return 0, err
+	}
+
+	s, _, err = c.extractParams(blankAsEmpty(s))
+	if err != nil {
+		return 0, err
+	}
+
+	if c.objectAsSingleArg && jsonKind(json.RawMessage(s)) == "object" {
+		return 1, nil
+	}
+
+	return CountParams(s)
+}
+
+// nearest returns the value of the sorted slice nearest to n, preferring the smaller.
+func nearest(values []int, n int) int {
+	v := values[0]
+	for _, c := range values[1:] {
+		if abs(c-n) < abs(v-n) {
+			v = c
+		}
+	}
+	return v
+}
+
+// containsInt returns true if values contains n.
+func containsInt(values []int, n int) bool {
+	for _, v := range values {
+		if v == n {
+			return true
+		}
+	}
+	return false
+}
+
+// abs returns the absolute value of n.
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,332 @@
+package jsoncall_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tj/assert"
+	jsoncall "github.com/tj/go-jsoncall"
+)
+
+// petService is a service registered via RegisterMethods.
+type petService struct {
+	names []string
+}
+
+func (s *petService) Add(ctx context.Context, name string) int {
+	s.names = append(s.names, name)
+	return len(s.names)
+}
+
+func (s *petService) Count() int {
+	return len(s.names)
+}
+
+func (s *petService) AddAll(names ...string) {
+	s.names = append(s.names, names...)
+}
+
+func (s *petService) Copy(from, to context.Context) {}
+
+func (s *petService) Lookup(name string) (error, int) { return nil, 0 }
+
+func (s *petService) reset() {}
+
+// Test registering and calling functions.
+func TestRegistry(t *testing.T) {
+	t.Run("should call registered functions", func(t *testing.T) {
+		r := jsoncall.NewRegistry()
+		assert.NoError(t, r.Register("add", add))
+
+		v, err := r.Call("add", `[1, 2]`)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, v[0].Interface())
+
+		_, err = r.Call("add", `[1]`)
+		assert.EqualError(t, err, `Too few arguments: expected 2, got 1`)
+	})
+
+	t.Run("should report the registered name via WithObserver", func(t *testing.T) {
+		r := jsoncall.NewRegistry()
+		assert.NoError(t, r.Register("math.add", add))
+
+		var name string
+		_, err := r.Call("math.add", `[1, 2]`, jsoncall.WithObserver(func(n string, dur time.Duration, err error) {
+			name = n
+		}))
+		assert.NoError(t, err)
+		assert.Equal(t, "math.add", name)
+	})
+
+	t.Run("should error on unknown names", func(t *testing.T) {
+		r := jsoncall.NewRegistry()
+		_, err := r.Call("add", `[1, 2]`)
+		assert.Equal(t, jsoncall.ErrMethodNotFound, err)
+		assert.Equal(t, 404, jsoncall.ErrorCode(err))
+	})
+
+	t.Run("should error on non-functions", func(t *testing.T) {
+		r := jsoncall.NewRegistry()
+		assert.Equal(t, jsoncall.ErrNotFunction, r.Register("add", 5))
+		assert.Equal(t, jsoncall.ErrMultipleContexts, r.Register("copy", func(a, b context.Context) {}))
+	})
+
+	t.Run("should resolve overloads by arity", func(t *testing.T) {
+		r := jsoncall.NewRegistry()
+		assert.NoError(t, r.Register("greet", func() string { return "Hello" }))
+		assert.NoError(t, r.Register("greet", func(ctx context.Context, name string) string { return "Hello " + name }))
+		assert.NoError(t, r.Register("greet", func(greeting, name string) string { return greeting + " " + name }))
+
+		v, err := r.Call("greet", `[]`)
+		assert.NoError(t, err)
+		assert.Equal(t, "Hello", v[0].Interface())
+
+		v, err = r.Call("greet", `["Tobi"]`)
+		assert.NoError(t, err)
+		assert.Equal(t, "Hello Tobi", v[0].Interface())
+
+		v, err = r.Call("greet", `["Hi", "Loki"]`)
+		assert.NoError(t, err)
+		assert.Equal(t, "Hi Loki", v[0].Interface())
+	})
+
+	t.Run("should error listing accepted counts when no overload matches", func(t *testing.T) {
+		r := jsoncall.NewRegistry()
+		assert.NoError(t, r.Register("greet", func(name string) string { return name }))
+		assert.NoError(t, r.Register("greet", func(greeting, name string) string { return name }))
+
+		_, err := r.Call("greet", `[1, 2, 3]`)
+		assert.EqualError(t, err, `Too many arguments: expected 1 or 2, got 3`)
+		assert.True(t, errors.Is(err, jsoncall.ErrTooManyArguments))
+
+		var e *jsoncall.ArityError
+		assert.True(t, errors.As(err, &e))
+		assert.Equal(t, []int{1, 2}, e.Accepted)
+
+		_, err = r.Call("greet", `[]`)
+		assert.EqualError(t, err, `Too few arguments: expected 1 or 2, got 0`)
+		assert.True(t, errors.Is(err, jsoncall.ErrTooFewArguments))
+
+		_, err = r.Call("greet", `[1, hey]`)
+		assert.EqualError(t, err, `Invalid JSON`)
+	})
+
+	t.Run("should fall back to variadic overloads", func(t *testing.T) {
+		r := jsoncall.NewRegistry()
+		assert.NoError(t, r.Register("join", func(sep string, parts ...string) string { return strings.Join(parts, sep) }))
+		assert.NoError(t, r.Register("join", func(sep string) string { return "" }))
+
+		v, err := r.Call("join", `["-"]`)
+		assert.NoError(t, err)
+		assert.Equal(t, "", v[0].Interface())
+
+		v, err = r.Call("join", `["-", "a", "b", "c"]`)
+		assert.NoError(t, err)
+		assert.Equal(t, "a-b-c", v[0].Interface())
+
+		_, err = r.Call("join", `[]`)
+		assert.EqualError(t, err, `Too few arguments: expected 1, got 0`)
+	})
+
+	t.Run("should resolve overloads with the call's options", func(t *testing.T) {
+		r := jsoncall.NewRegistry()
+		assert.NoError(t, r.Register("add", add))
+		assert.NoError(t, r.Register("add", func(a, b, c int) int { return a + b + c }))
+		assert.NoError(t, r.Register("save", func(u User) string { return u.Name }))
+		assert.NoError(t, r.Register("save", func(u User, note string) string { return note }))
+		assert.NoError(t, r.Register("log", func(prefix, msg string) string { return prefix + msg }))
+		assert.NoError(t, r.Register("log", func(prefix, a, b string) string { return prefix + a + b }))
+		assert.NoError(t, r.Register("hello", func(log Logger, name string) string { return "Hello " + name }))
+		assert.NoError(t, r.Register("hello", func(a, b, c string) string { return a }))
+
+		v, err := r.Call("save", `{ "name": "Tobi" }`, jsoncall.WithObjectAsSingleArg())
+		assert.NoError(t, err)
+		assert.Equal(t, "Tobi", v[0].Interface())
+
+		v, err = r.Call("log", `["hi"]`, jsoncall.WithInjected(reflect.ValueOf("> ")))
+		assert.NoError(t, err)
+		assert.Equal(t, "> hi", v[0].Interface())
+
+		_, err = r.Call("hello", `["Tobi"]`)
+		assert.EqualError(t, err, `Too few arguments: expected 2 or 3, got 1`)
+
+		logger := reflect.TypeOf((*Logger)(nil)).Elem()
+		v, err = r.Call("hello", `["Tobi"]`, jsoncall.WithContextTypes(logger), jsoncall.WithContextFunc(func() context.Context {
+			return contextLogger{context.Background()}
+		}))
+		assert.NoError(t, err)
+		assert.Equal(t, "Hello Tobi", v[0].Interface())
+
+		v, err = r.Call("add", "1,2", jsoncall.WithCodec(csvCodec{}))
+		assert.NoError(t, err)
+		assert.Equal(t, 3, v[0].Interface())
+	})
+
+	t.Run("should error on duplicate arities", func(t *testing.T) {
+		r := jsoncall.NewRegistry()
+		assert.NoError(t, r.Register("add", add))
+		assert.Equal(t, jsoncall.ErrDuplicateArity, r.Register("add", func(ctx context.Context, a, b int) int { return 0 }))
+	})
+}
+
+// Test validation of registered names.
+func TestRegistry_SetNameValidator(t *testing.T) {
+	t.Run("should accept valid names by default", func(t *testing.T) {
+		r := jsoncall.NewRegistry()
+		for _, name := range []string{"add", "Add", "math.add", "math_v2.Add", "a1"} {
+			assert.NoError(t, r.Register(name, add), name)
+		}
+		assert.NoError(t, r.Alias("sum", "add"))
+	})
+
+	t.Run("should reject invalid names by default", func(t *testing.T) {
+		r := jsoncall.NewRegistry()
+		for _, name := range []string{"", "1add", ".add", "_add", "add-numbers", "add numbers", "add()", "math/add", "añadir"} {
+			err := r.Register(name, add)
+			assert.True(t, errors.Is(err, jsoncall.ErrInvalidName), name)
+
+			var e *jsoncall.NameError
+			assert.True(t, errors.As(err, &e))
+			assert.Equal(t, name, e.Name)
+		}
+
+		assert.EqualError(t, r.Alias("add-numbers", "add"), `Invalid function name "add-numbers"`)
+		assert.Equal(t, 500, jsoncall.ErrorCode(r.Register("$add", add)))
+	})
+
+	t.Run("should use a custom validator", func(t *testing.T) {
+		r := jsoncall.NewRegistry()
+		r.SetNameValidator(func(name string) error {
+			if !strings.HasPrefix(name, "pets.") {
+				return errors.New("must be under pets")
+			}
+			return jsoncall.ValidateName(name)
+		})
+
+		assert.NoError(t, r.Register("pets.add", add))
+		assert.EqualError(t, r.Register("add", add), `must be under pets`)
+		assert.True(t, errors.Is(r.Register("pets.add-all", add), jsoncall.ErrInvalidName))
+		assert.EqualError(t, jsoncall.RegisterMethods(r, &petService{}, "animals."), `must be under pets`)
+	})
+
+	t.Run("should disable validation with nil", func(t *testing.T) {
+		r := jsoncall.NewRegistry()
+		r.SetNameValidator(nil)
+		assert.NoError(t, r.Register("math/add", add))
+
+		v, err := r.Call("math/add", `[1, 2]`)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, v[0].Interface())
+	})
+}
+
+// Test aliasing names.
+func TestRegistry_Alias(t *testing.T) {
+	t.Run("should call aliased functions", func(t *testing.T) {
+		r := jsoncall.NewRegistry()
+		assert.NoError(t, r.Register("math.add", add))
+		assert.NoError(t, r.Alias("add", "math.add"))
+		assert.NoError(t, r.Alias("sum", "add"))
+
+		v, err := r.Call("add", `[1, 2]`)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, v[0].Interface())
+
+		v, err = r.Call("sum", `[2, 3]`)
+		assert.NoError(t, err)
+		assert.Equal(t, 5, v[0].Interface())
+	})
+
+	t.Run("should resolve aliases registered before their target", func(t *testing.T) {
+		r := jsoncall.NewRegistry()
+		assert.NoError(t, r.Alias("add", "math.add"))
+
+		_, err := r.Call("add", `[1, 2]`)
+		assert.Equal(t, jsoncall.ErrMethodNotFound, err)
+
+		assert.NoError(t, r.Register("math.add", add))
+		v, err := r.Call("add", `[1, 2]`)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, v[0].Interface())
+	})
+
+	t.Run("should prefer registered functions over aliases", func(t *testing.T) {
+		r := jsoncall.NewRegistry()
+		assert.NoError(t, r.Register("math.add", add))
+		assert.NoError(t, r.Register("add", func(a, b int) int { return 0 }))
+		assert.NoError(t, r.Alias("add", "math.add"))
+
+		v, err := r.Call("add", `[1, 2]`)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, v[0].Interface())
+	})
+
+	t.Run("should error on cycles", func(t *testing.T) {
+		r := jsoncall.NewRegistry()
+		assert.Equal(t, jsoncall.ErrAliasCycle, r.Alias("add", "add"))
+
+		assert.NoError(t, r.Alias("a", "b"))
+		assert.NoError(t, r.Alias("b", "c"))
+		assert.Equal(t, jsoncall.ErrAliasCycle, r.Alias("c", "a"))
+
+		_, err := r.Call("a", `[]`)
+		assert.Equal(t, jsoncall.ErrMethodNotFound, err)
+	})
+}
+
+// Test counting of params.
+func TestCountParams(t *testing.T) {
+	n, err := jsoncall.CountParams(``)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+
+	n, err = jsoncall.CountParams(`[1, [2, 3], { "a": 4 }]`)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+
+	_, err = jsoncall.CountParams(`[1,`)
+	assert.EqualError(t, err, `Invalid JSON`)
+}
+
+// Test registering the methods of a receiver.
+func TestRegisterMethods(t *testing.T) {
+	t.Run("should register exported methods bound to the receiver", func(t *testing.T) {
+		r := jsoncall.NewRegistry()
+		s := &petService{}
+		assert.NoError(t, jsoncall.RegisterMethods(r, s, "pets."))
+
+		v, err := r.Call("pets.Add", `["Tobi"]`)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, v[0].Interface())
+
+		v, err = r.Call("pets.Count", `[]`)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, v[0].Interface())
+		assert.Equal(t, []string{"Tobi"}, s.names)
+
+		_, err = r.Call("pets.AddAll", `["Loki", "Jane"]`)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Tobi", "Loki", "Jane"}, s.names)
+	})
+
+	t.Run("should skip methods which can't be called from JSON", func(t *testing.T) {
+		r := jsoncall.NewRegistry()
+		assert.NoError(t, jsoncall.RegisterMethods(r, &petService{}, ""))
+
+		for _, name := range []string{"Copy", "Lookup", "reset"} {
+			_, err := r.Call(name, `[]`)
+			assert.True(t, errors.Is(err, jsoncall.ErrMethodNotFound), name)
+		}
+	})
+
+	t.Run("should report registration errors", func(t *testing.T) {
+		r := jsoncall.NewRegistry()
+		assert.NoError(t, jsoncall.RegisterMethods(r, &petService{}, "pets."))
+		assert.Equal(t, jsoncall.ErrDuplicateArity, jsoncall.RegisterMethods(r, &petService{}, "pets."))
+		assert.Equal(t, jsoncall.ErrReceiverType, jsoncall.RegisterMethods(r, nil, "pets."))
+	})
+}
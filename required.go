@@ -0,0 +1,118 @@
+package jsoncall
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// ErrMissingField is returned when a required struct field is absent.
+var ErrMissingField error = newCallError(http.StatusBadRequest, "missing_field", "Missing required field")
+
+// MissingFieldError is returned when the object for a struct parameter is
+// missing a field tagged `jsoncall:"required"`.
+type MissingFieldError struct {
+	Field string
+}
+
+// Error implementation.
+func (e *MissingFieldError) Error() string {
+	return fmt.Sprintf("Missing required field %q", e.Field)
+}
+
+// Unwrap returns ErrMissingField.
+func (e *MissingFieldError) Unwrap() error {
+	return ErrMissingField
+}
+
+// WithRequiredFields enforces the presence of struct parameter fields tagged
+// `jsoncall:"required"`, returning a *MissingFieldError wrapped in an
+// *ArgumentError when the key is absent. Keys are matched case-insensitively
+// as encoding/json does.
+func WithRequiredFields() Option {
+	return func(v *config) {
+		v.requiredFields = true
+	}
+}
+
+// checkRequired returns an error if raw is an object missing any required
+// fields of the struct type t.
+func checkRequired(t reflect.Type, raw json.RawMessage) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		// defer to the decoder for non-objects
+		return nil
+	}
+
+	return missingField(t, fields)
+}
+
+// missingField returns an error for the first required field of the struct
+// type t which is absent from fields, including those promoted from embedded
+// structs.
+func missingField(t reflect.Type, fields map[string]json.RawMessage) error {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		if isPromoted(f) {
+			if err := missingField(unrollPointer(f.Type), fields); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !isRequired(f) {
+			continue
+		}
+
+		name := fieldName(f)
+		if !hasKey(fields, name) {
+			return &MissingFieldError{Field: name}
+		}
+	}
+
+	return nil
+}
+
+// isPromoted returns true if the fields of an embedded struct are promoted
+// into its parent's object, which is the case unless it is given a json name.
+func isPromoted(f reflect.StructField) bool {
+	tag := f.Tag.Get("json")
+	return f.Anonymous && tag != "-" && strings.Split(tag, ",")[0] == "" && unrollPointer(f.Type).Kind() == reflect.Struct
+}
+
+// isRequired returns true if the field is tagged as required.
+func isRequired(f reflect.StructField) bool {
+	for _, opt := range strings.Split(f.Tag.Get("jsoncall"), ",") {
+		if opt == "required" {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldName returns the JSON key of a struct field.
+func fieldName(f reflect.StructField) string {
+	name := strings.Split(f.Tag.Get("json"), ",")[0]
+	if name == "" {
+		return f.Name
+	}
+	return name
+}
+
+// hasKey returns true if the object has the given key, ignoring case.
+func hasKey(fields map[string]json.RawMessage, name string) bool {
+	if _, ok := fields[name]; ok {
+		return true
+	}
+
+	for k := range fields {
+		if strings.EqualFold(k, name) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,85 @@
+package jsoncall_test
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/tj/assert"
+	jsoncall "github.com/tj/go-jsoncall"
+)
+
+// Signup is a struct with required fields.
+type Signup struct {
+	Name  string `json:"name" jsoncall:"required"`
+	Email string `json:"email" jsoncall:"required"`
+	Bio   string `json:"bio"`
+	Plan  string `jsoncall:"required"`
+}
+
+// Record is embedded in other structs.
+type Record struct {
+	ID int `json:"id" jsoncall:"required"`
+}
+
+// Post is a struct with an embedded struct.
+type Post struct {
+	Record
+	Title string `json:"title" jsoncall:"required"`
+}
+
+// Test required field enforcement.
+func TestWithRequiredFields(t *testing.T) {
+	fn := func(id int, s *Signup) {}
+
+	t.Run("should decode when required fields are present", func(t *testing.T) {
+		args, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[1, { "name": "Tobi", "email": "", "plan": "pro" }]`, jsoncall.WithRequiredFields())
+		assert.NoError(t, err)
+		assert.Equal(t, &Signup{Name: "Tobi", Plan: "pro"}, args[1].Interface())
+	})
+
+	t.Run("should error on missing fields", func(t *testing.T) {
+		_, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[1, { "name": "Tobi", "Plan": "pro" }]`, jsoncall.WithRequiredFields())
+		assert.EqualError(t, err, `Argument 1: Missing required field "email"`)
+		assert.True(t, errors.Is(err, jsoncall.ErrMissingField))
+		assert.Equal(t, http.StatusBadRequest, jsoncall.ErrorCode(err))
+		assert.Equal(t, "missing_field", jsoncall.ErrorCategory(err))
+
+		var e *jsoncall.MissingFieldError
+		assert.True(t, errors.As(err, &e))
+		assert.Equal(t, "email", e.Field)
+	})
+
+	t.Run("should use field names for untagged fields", func(t *testing.T) {
+		_, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[1, { "name": "Tobi", "email": "tobi@apex.sh" }]`, jsoncall.WithRequiredFields())
+		assert.EqualError(t, err, `Argument 1: Missing required field "Plan"`)
+	})
+
+	t.Run("should ignore null for pointer parameters", func(t *testing.T) {
+		args, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[1, null]`, jsoncall.WithRequiredFields())
+		assert.NoError(t, err)
+		assert.Nil(t, args[1].Interface())
+	})
+
+	t.Run("should defer to the decoder for non-objects", func(t *testing.T) {
+		_, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[1, "Tobi"]`, jsoncall.WithRequiredFields())
+		assert.EqualError(t, err, `Incorrect type string, expected object`)
+	})
+
+	t.Run("should enforce fields of embedded structs", func(t *testing.T) {
+		fn := func(p Post) {}
+
+		args, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[{ "id": 1, "title": "Hello" }]`, jsoncall.WithRequiredFields())
+		assert.NoError(t, err)
+		assert.Equal(t, Post{Record: Record{ID: 1}, Title: "Hello"}, args[0].Interface())
+
+		_, err = jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[{ "title": "Hello" }]`, jsoncall.WithRequiredFields())
+		assert.EqualError(t, err, `Argument 0: Missing required field "id"`)
+	})
+
+	t.Run("should not enforce fields by default", func(t *testing.T) {
+		_, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(fn), `[1, {}]`)
+		assert.NoError(t, err)
+	})
+}
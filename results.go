@@ -0,0 +1,102 @@
+package jsoncall
+
+import (
+	"reflect"
+)
+
+// Results are the results of a call, including any nil error result.
+//
+// The values are those returned by the function, which never reference the
+// pooled storage used for decoding arguments, so they remain valid after
+// the call. The slice returned by Values is shared however, use Detach for
+// results which are retained while the original may be modified.
+type Results struct {
+	values []reflect.Value
+	fn     reflect.Type
+}
+
+// NewResults returns results for the given values.
+func NewResults(values []reflect.Value) Results {
+	return Results{values: values}
+}
+
+// CallFuncResults invokes a function with arguments derived from a json
+// string, returning its results. On error the results are empty, however
+// ExpectedLen still reports the function's number of results, so responses
+// may be shaped consistently.
+func CallFuncResults(fn interface{}, args string, options ...Option) (Results, error) {
+	var r Results
+	if t := reflect.TypeOf(fn); t != nil && t.Kind() == reflect.Func {
+		r.fn = t
+	}
+
+	values, err := CallFunc(fn, args, options...)
+	if err != nil {
+		return r, err
+	}
+
+	r.values = values
+	return r, nil
+}
+
+// Detach returns a copy of the results whose values are held in storage of
+// their own, independent of the original values and slice, so that they are
+// safe to retain. The copy is shallow, so pointers, slices and maps continue
+// to reference what the function returned.
+func (r Results) Detach() Results {
+	d := Results{fn: r.fn}
+	if r.values == nil {
+		return d
+	}
+
+	d.values = make([]reflect.Value, len(r.values))
+	for i, v := range r.values {
+		if !v.IsValid() {
+			continue
+		}
+
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		d.values[i] = c
+	}
+
+	return d
+}
+
+// Values returns the result values.
+func (r Results) Values() []reflect.Value {
+	return r.values
+}
+
+// Len returns the number of results.
+func (r Results) Len() int {
+	return len(r.values)
+}
+
+// ExpectedLen returns the number of results declared by the function,
+// including any error result, regardless of whether the call succeeded. For
+// results created with NewResults it is the same as Len.
+func (r Results) ExpectedLen() int {
+	if r.fn == nil {
+		return r.Len()
+	}
+	return r.fn.NumOut()
+}
+
+// At returns the result at index i.
+func (r Results) At(i int) interface{} {
+	return r.values[i].Interface()
+}
+
+// First returns the first result, or nil when there are no results.
+func (r Results) First() interface{} {
+	if len(r.values) == 0 {
+		return nil
+	}
+	return r.At(0)
+}
+
+// JSON returns the JSON representation of the results, see MarshalResults.
+func (r Results) JSON(options ...Option) ([]byte, error) {
+	return MarshalResults(r.values, options...)
+}
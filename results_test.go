@@ -0,0 +1,137 @@
+package jsoncall_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/tj/assert"
+	jsoncall "github.com/tj/go-jsoncall"
+)
+
+// Test calling of functions with results.
+func TestCallFuncResults(t *testing.T) {
+	t.Run("should support a single result", func(t *testing.T) {
+		r, err := jsoncall.CallFuncResults(add, `[1, 2]`)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, r.Len())
+		assert.Equal(t, 3, r.First())
+		assert.Equal(t, 3, r.At(0))
+
+		b, err := r.JSON()
+		assert.NoError(t, err)
+		assert.Equal(t, `3`, string(b))
+	})
+
+	t.Run("should support multiple results", func(t *testing.T) {
+		minmax := func(a, b int) (int, int, error) { return a, b, nil }
+		r, err := jsoncall.CallFuncResults(minmax, `[1, 2]`)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, r.Len())
+		assert.Equal(t, 1, r.First())
+		assert.Equal(t, 2, r.At(1))
+		assert.Nil(t, r.At(2))
+		assert.Len(t, r.Values(), 3)
+
+		b, err := r.JSON()
+		assert.NoError(t, err)
+		assert.Equal(t, `[1,2]`, string(b))
+	})
+
+	t.Run("should support no results", func(t *testing.T) {
+		r, err := jsoncall.CallFuncResults(func() {}, `[]`)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, r.Len())
+		assert.Nil(t, r.First())
+
+		b, err := r.JSON()
+		assert.NoError(t, err)
+		assert.Equal(t, `null`, string(b))
+	})
+
+	t.Run("should support returning errors", func(t *testing.T) {
+		fn := func() (int, error) { return 0, errors.New("boom") }
+		_, err := jsoncall.CallFuncResults(fn, `[]`)
+		assert.EqualError(t, err, `boom`)
+	})
+
+	t.Run("should report the expected length on error", func(t *testing.T) {
+		fn := func(fail bool) (int, int, error) {
+			if fail {
+				return 0, 0, errors.New("boom")
+			}
+			return 1, 2, nil
+		}
+
+		r, err := jsoncall.CallFuncResults(fn, `[true]`)
+		assert.EqualError(t, err, `boom`)
+		assert.Equal(t, 0, r.Len())
+		assert.Equal(t, 3, r.ExpectedLen())
+
+		r, err = jsoncall.CallFuncResults(fn, `[]`)
+		assert.Error(t, err)
+		assert.Equal(t, 3, r.ExpectedLen())
+
+		r, err = jsoncall.CallFuncResults(fn, `[false]`)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, r.Len())
+		assert.Equal(t, 3, r.ExpectedLen())
+	})
+
+	t.Run("should default the expected length to the length", func(t *testing.T) {
+		r := jsoncall.NewResults([]reflect.Value{reflect.ValueOf(1)})
+		assert.Equal(t, 1, r.ExpectedLen())
+	})
+}
+
+// Test detaching results.
+func TestResults_Detach(t *testing.T) {
+	t.Run("should copy values into independent storage", func(t *testing.T) {
+		r, err := jsoncall.CallFuncResults(add, `[1, 2]`)
+		assert.NoError(t, err)
+
+		d := r.Detach()
+		r.Values()[0] = reflect.ValueOf(10)
+
+		assert.Equal(t, 10, r.First())
+		assert.Equal(t, 3, d.First())
+		assert.Equal(t, 1, d.Len())
+		assert.Equal(t, r.ExpectedLen(), d.ExpectedLen())
+
+		d.Values()[0].SetInt(5)
+		assert.Equal(t, 5, d.First())
+		assert.Equal(t, 10, r.First())
+	})
+
+	t.Run("should retain results across calls reusing arguments", func(t *testing.T) {
+		fn := func(u User) User { return u }
+
+		var retained []jsoncall.Results
+		for _, name := range []string{"Tobi", "Loki", "Jane"} {
+			r, err := jsoncall.CallFuncResults(fn, `[{ "name": "`+name+`" }]`)
+			assert.NoError(t, err)
+			retained = append(retained, r.Detach())
+		}
+
+		for i, name := range []string{"Tobi", "Loki", "Jane"} {
+			assert.Equal(t, name, retained[i].First().(User).Name)
+		}
+	})
+
+	t.Run("should preserve nil errors and empty results", func(t *testing.T) {
+		fn := func() (int, error) { return 1, nil }
+		r, err := jsoncall.CallFuncResults(fn, `[]`)
+		assert.NoError(t, err)
+
+		d := r.Detach()
+		assert.Equal(t, 2, d.Len())
+		assert.Nil(t, d.At(1))
+
+		r, err = jsoncall.CallFuncResults(addPet, `["Tobi"]`)
+		assert.Error(t, err)
+
+		d = r.Detach()
+		assert.Equal(t, 0, d.Len())
+		assert.Equal(t, r.ExpectedLen(), d.ExpectedLen())
+	})
+}
@@ -0,0 +1,69 @@
+package jsoncall
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Signature returns a human-readable description of a function's signature
+// using JSON type names, for example "(ctx, number, number) -> number".
+// Context parameters are shown as "ctx" and error results as "error". An
+// empty string is returned if t is nil or not a function.
+func Signature(t reflect.Type) string {
+	if t == nil || t.Kind() != reflect.Func {
+		return ""
+	}
+	return signature(t, false)
+}
+
+// MethodSignature returns a human-readable description of a method's
+// signature as Signature does, excluding the receiver.
+func MethodSignature(m reflect.Method) string {
+	if m.Type == nil {
+		return ""
+	}
+	return signature(m.Type, true)
+}
+
+// signature implementation.
+func signature(t reflect.Type, isMethod bool) string {
+	var params []string
+	for i := paramOffset(isMethod); i < t.NumIn(); i++ {
+		p := t.In(i)
+		if isContext(p) {
+			params = append(params, "ctx")
+		} else {
+			params = append(params, paramName(t, i))
+		}
+	}
+
+	var results []string
+	for i := 0; i < t.NumOut(); i++ {
+		r := t.Out(i)
+		if isError(r) {
+			results = append(results, "error")
+		} else {
+			results = append(results, typeName(r))
+		}
+	}
+
+	s := "(" + strings.Join(params, ", ") + ")"
+
+	switch len(results) {
+	case 0:
+		return s
+	case 1:
+		return s + " -> " + results[0]
+	default:
+		return s + " -> (" + strings.Join(results, ", ") + ")"
+	}
+}
+
+// paramName returns the JSON type name of parameter i of t, prefixed with
+// "..." for the final parameter of variadic functions.
+func paramName(t reflect.Type, i int) string {
+	if t.IsVariadic() && i == t.NumIn()-1 {
+		return "..." + typeName(t.In(i).Elem())
+	}
+	return typeName(t.In(i))
+}
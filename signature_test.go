@@ -0,0 +1,45 @@
+package jsoncall_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/tj/assert"
+	jsoncall "github.com/tj/go-jsoncall"
+)
+
+// Test function signature descriptions.
+func TestSignature(t *testing.T) {
+	cases := []struct {
+		fn     interface{}
+		output string
+	}{
+		{func() {}, "()"},
+		{add, "(number, number) -> number"},
+		{sum, "(...number) -> number"},
+		{addUser, "(object) -> error"},
+		{addUserContext, "(ctx, object) -> error"},
+		{addTags, "(object) -> error"},
+		{addUsers, "(array of objects) -> error"},
+		{func(ctx context.Context, id string) (bool, error) { return false, nil }, "(ctx, string) -> (boolean, error)"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.output, func(t *testing.T) {
+			assert.Equal(t, c.output, jsoncall.Signature(reflect.TypeOf(c.fn)))
+		})
+	}
+
+	t.Run("should return an empty string for non-functions", func(t *testing.T) {
+		assert.Equal(t, "", jsoncall.Signature(reflect.TypeOf(1)))
+		assert.Equal(t, "", jsoncall.Signature(nil))
+		assert.Equal(t, "", jsoncall.MethodSignature(reflect.Method{}))
+	})
+}
+
+// Test method signature descriptions.
+func TestMethodSignature(t *testing.T) {
+	m, _ := reflect.TypeOf(&mathService{}).MethodByName("Sum")
+	assert.Equal(t, "(ctx, array of numbers) -> number", jsoncall.MethodSignature(m))
+}
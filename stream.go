@@ -0,0 +1,121 @@
+package jsoncall
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+)
+
+// ErrNotChannel is returned when a streamed function does not return a channel.
+var ErrNotChannel error = newCallError(http.StatusInternalServerError, "not_channel", "Function must return a receivable channel")
+
+// CallFuncStream invokes a function with arguments derived from a json string,
+// where the sole non-error result is a channel. Each element received from the
+// channel is marshaled and forwarded until the channel is closed, at which
+// point both returned channels are closed. At most one error is delivered.
+//
+// Forwarding stops when the injected context, or the one given by
+// WithContext, is done, so callers which stop reading should cancel it.
+// After an error or cancellation the channel is drained until closed, so
+// that the function's sending goroutine isn't blocked.
+func CallFuncStream(fn interface{}, args string, options ...Option) (<-chan json.RawMessage, <-chan error) {
+	out := make(chan json.RawMessage)
+	errs := make(chan error, 1)
+
+	ctx, values, err := CallFuncCtx(fn, args, options...)
+	if err != nil {
+		errs <- err
+		close(out)
+		close(errs)
+		return out, errs
+	}
+
+	ch, err := resultChannel(values)
+	if err != nil {
+		errs <- err
+		close(out)
+		close(errs)
+		return out, errs
+	}
+
+	if ctx == nil {
+		ctx = newConfig(options).context()
+	}
+
+	go func() {
+		err := forward(ctx, ch, out)
+		if err != nil {
+			errs <- err
+		}
+		close(out)
+		close(errs)
+
+		if err != nil {
+			drain(ch)
+		}
+	}()
+
+	return out, errs
+}
+
+// forward marshals each element received from ch and sends it on out, until
+// ch is closed or ctx is done.
+func forward(ctx context.Context, ch reflect.Value, out chan<- json.RawMessage) error {
+	cases := []reflect.SelectCase{
+		{Dir: reflect.SelectRecv, Chan: ch},
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())},
+	}
+
+	for {
+		chosen, v, ok := reflect.Select(cases)
+		if chosen == 1 {
+			return ctx.Err()
+		}
+
+		if !ok {
+			return nil
+		}
+
+		b, err := json.Marshal(v.Interface())
+		if err != nil {
+			return err
+		}
+
+		select {
+		case out <- b:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// drain receives from ch until it is closed.
+func drain(ch reflect.Value) {
+	for {
+		if _, ok := ch.Recv(); !ok {
+			return
+		}
+	}
+}
+
+// resultChannel returns the sole non-error result, which must be a receivable channel.
+func resultChannel(values []reflect.Value) (reflect.Value, error) {
+	var results []reflect.Value
+	for _, v := range values {
+		if !isError(v.Type()) {
+			results = append(results, v)
+		}
+	}
+
+	if len(results) != 1 {
+		return reflect.Value{}, ErrNotChannel
+	}
+
+	ch := results[0]
+	if ch.Kind() != reflect.Chan || ch.Type().ChanDir()&reflect.RecvDir == 0 || ch.IsNil() {
+		return reflect.Value{}, ErrNotChannel
+	}
+
+	return ch, nil
+}
@@ -0,0 +1,140 @@
+package jsoncall_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/tj/assert"
+	jsoncall "github.com/tj/go-jsoncall"
+)
+
+// collect returns the elements and error of a stream.
+func collect(out <-chan json.RawMessage, errs <-chan error) (values []string, err error) {
+	for b := range out {
+		values = append(values, string(b))
+	}
+	return values, <-errs
+}
+
+// Test streaming of channel results.
+func TestCallFuncStream(t *testing.T) {
+	t.Run("should forward elements until the channel is closed", func(t *testing.T) {
+		users := func(names []string) <-chan User {
+			ch := make(chan User)
+			go func() {
+				defer close(ch)
+				for _, name := range names {
+					ch <- User{Name: name}
+				}
+			}()
+			return ch
+		}
+
+		values, err := collect(jsoncall.CallFuncStream(users, `[["Tobi", "Loki"]]`))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{`{"name":"Tobi","email":""}`, `{"name":"Loki","email":""}`}, values)
+	})
+
+	t.Run("should support channels alongside errors", func(t *testing.T) {
+		nums := func(n int) (chan int, error) {
+			ch := make(chan int, n)
+			for i := 0; i < n; i++ {
+				ch <- i
+			}
+			close(ch)
+			return ch, nil
+		}
+
+		values, err := collect(jsoncall.CallFuncStream(nums, `[3]`))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{`0`, `1`, `2`}, values)
+	})
+
+	t.Run("should deliver call errors", func(t *testing.T) {
+		fail := func() (chan int, error) { return nil, errors.New("boom") }
+		values, err := collect(jsoncall.CallFuncStream(fail, `[]`))
+		assert.EqualError(t, err, `boom`)
+		assert.Len(t, values, 0)
+
+		values, err = collect(jsoncall.CallFuncStream(add, `[1]`))
+		assert.EqualError(t, err, `Too few arguments: expected 2, got 1`)
+		assert.Len(t, values, 0)
+	})
+
+	t.Run("should drain the channel after marshal errors", func(t *testing.T) {
+		done := make(chan struct{})
+		funcs := func() <-chan interface{} {
+			ch := make(chan interface{})
+			go func() {
+				defer close(done)
+				defer close(ch)
+				ch <- 1
+				ch <- func() {}
+				ch <- 2
+				ch <- 3
+			}()
+			return ch
+		}
+
+		values, err := collect(jsoncall.CallFuncStream(funcs, `[]`))
+		assert.EqualError(t, err, `json: unsupported type: func()`)
+		assert.Equal(t, []string{`1`}, values)
+		<-done
+	})
+
+	t.Run("should stop when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+
+		nums := func(ctx context.Context) <-chan int {
+			ch := make(chan int)
+			go func() {
+				defer close(done)
+				defer close(ch)
+				for i := 0; ; i++ {
+					select {
+					case ch <- i:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}()
+			return ch
+		}
+
+		out, errs := jsoncall.CallFuncStream(nums, `[]`, jsoncall.WithContext(ctx))
+		assert.Equal(t, `0`, string(<-out))
+		cancel()
+
+		assert.Equal(t, context.Canceled, <-errs)
+		<-done
+	})
+
+	t.Run("should stop when the context given is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		nums := func() chan int {
+			ch := make(chan int, 1)
+			ch <- 1
+			close(ch)
+			return ch
+		}
+
+		out, errs := jsoncall.CallFuncStream(nums, `[]`, jsoncall.WithContext(ctx))
+		cancel()
+
+		assert.Equal(t, context.Canceled, <-errs)
+		_, ok := <-out
+		assert.False(t, ok)
+	})
+
+	t.Run("should error when the result is not a channel", func(t *testing.T) {
+		_, err := collect(jsoncall.CallFuncStream(add, `[1, 2]`))
+		assert.Equal(t, jsoncall.ErrNotChannel, err)
+
+		send := func() chan<- int { return make(chan int) }
+		_, err = collect(jsoncall.CallFuncStream(send, `[]`))
+		assert.Equal(t, jsoncall.ErrNotChannel, err)
+	})
+}
@@ -0,0 +1,122 @@
+package jsoncall
+
+import (
+	"context"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// contextKey is a context key.
+type contextKey struct {
+	name string
+}
+
+// SpanContextKey is the context key under which the SpanContext parsed by
+// WithTraceparent is stored in the injected context.
+var SpanContextKey = &contextKey{"span context"}
+
+// ErrInvalidTraceparent is returned when a traceparent value is malformed.
+var ErrInvalidTraceparent error = newCallError(http.StatusBadRequest, "invalid_traceparent", "Invalid traceparent")
+
+// SpanContext is a W3C trace context, as carried by the traceparent header.
+type SpanContext struct {
+	Version  byte
+	TraceID  string
+	ParentID string
+	Flags    byte
+}
+
+// Sampled returns true if the sampled flag is set.
+func (s SpanContext) Sampled() bool {
+	return s.Flags&1 == 1
+}
+
+// String returns the traceparent representation.
+func (s SpanContext) String() string {
+	return hex.EncodeToString([]byte{s.Version}) + "-" + s.TraceID + "-" + s.ParentID + "-" + hex.EncodeToString([]byte{s.Flags})
+}
+
+// SpanContextFromContext returns the span context stored by WithTraceparent.
+func SpanContextFromContext(ctx context.Context) (SpanContext, bool) {
+	s, ok := ctx.Value(SpanContextKey).(SpanContext)
+	return s, ok
+}
+
+// WithTraceparent parses a W3C traceparent header value and stores the
+// resulting SpanContext in the injected context, under SpanContextKey.
+// Invalid values are ignored, as the specification requires.
+func WithTraceparent(tp string) Option {
+	return func(v *config) {
+		s, err := ParseTraceparent(tp)
+		if err != nil {
+			v.spanContext = nil
+			return
+		}
+		v.spanContext = &s
+	}
+}
+
+// ParseTraceparent parses a W3C traceparent header value.
+func ParseTraceparent(tp string) (SpanContext, error) {
+	var s SpanContext
+
+	tp = strings.TrimSpace(tp)
+	if len(tp) < 55 || tp[2] != '-' || tp[35] != '-' || tp[52] != '-' {
+		return s, ErrInvalidTraceparent
+	}
+
+	version, ok := parseHexByte(tp[0:2])
+	if !ok || version == 0xff {
+		return s, ErrInvalidTraceparent
+	}
+
+	// version 00 has a fixed length, future versions may append fields
+	if (version == 0 && len(tp) != 55) || (len(tp) > 55 && tp[55] != '-') {
+		return s, ErrInvalidTraceparent
+	}
+
+	traceID := tp[3:35]
+	parentID := tp[36:52]
+	if !isHex(traceID) || isZero(traceID) || !isHex(parentID) || isZero(parentID) {
+		return s, ErrInvalidTraceparent
+	}
+
+	flags, ok := parseHexByte(tp[53:55])
+	if !ok {
+		return s, ErrInvalidTraceparent
+	}
+
+	s.Version = version
+	s.TraceID = traceID
+	s.ParentID = parentID
+	s.Flags = flags
+	return s, nil
+}
+
+// parseHexByte parses a two character lowercase hex string.
+func parseHexByte(s string) (byte, bool) {
+	if !isHex(s) {
+		return 0, false
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return 0, false
+	}
+	return b[0], true
+}
+
+// isHex returns true if s consists only of lowercase hex characters.
+func isHex(s string) bool {
+	for _, c := range s {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// isZero returns true if s consists only of zeros.
+func isZero(s string) bool {
+	return strings.Trim(s, "0") == ""
+}
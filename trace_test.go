@@ -0,0 +1,62 @@
+package jsoncall_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/tj/assert"
+	jsoncall "github.com/tj/go-jsoncall"
+)
+
+// Test parsing of traceparent values.
+func TestParseTraceparent(t *testing.T) {
+	t.Run("should parse valid values", func(t *testing.T) {
+		s, err := jsoncall.ParseTraceparent(`00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01`)
+		assert.NoError(t, err)
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", s.TraceID)
+		assert.Equal(t, "00f067aa0ba902b7", s.ParentID)
+		assert.True(t, s.Sampled())
+		assert.Equal(t, `00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01`, s.String())
+	})
+
+	t.Run("should error on invalid values", func(t *testing.T) {
+		cases := []string{
+			``,
+			`00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7`,
+			`00-00000000000000000000000000000000-00f067aa0ba902b7-01`,
+			`00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01`,
+			`00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01`,
+			`ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01`,
+			`00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra`,
+		}
+
+		for _, c := range cases {
+			_, err := jsoncall.ParseTraceparent(c)
+			assert.Equal(t, jsoncall.ErrInvalidTraceparent, err, c)
+		}
+	})
+}
+
+// Test propagation of trace context.
+func TestWithTraceparent(t *testing.T) {
+	t.Run("should store the span context in the injected context", func(t *testing.T) {
+		var traceID string
+		fn := func(ctx context.Context) {
+			s, ok := jsoncall.SpanContextFromContext(ctx)
+			assert.True(t, ok)
+			traceID = s.TraceID
+		}
+
+		_, err := jsoncall.CallFunc(fn, `[]`, jsoncall.WithTraceparent(`00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01`))
+		assert.NoError(t, err)
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", traceID)
+	})
+
+	t.Run("should ignore invalid values", func(t *testing.T) {
+		vals, err := jsoncall.ArgumentsOfFunc(reflect.TypeOf(addUserContext), `[{}]`, jsoncall.WithTraceparent(`invalid`))
+		assert.NoError(t, err)
+		_, ok := jsoncall.SpanContextFromContext(vals[0].Interface().(context.Context))
+		assert.False(t, ok)
+	})
+}
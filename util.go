@@ -1,25 +1,88 @@
 package jsoncall
 
 import (
+	"bytes"
 	"context"
+	"encoding"
+	"encoding/json"
+	"io"
+	"math"
+	"math/big"
+	"net/url"
 	"reflect"
+	"runtime"
+	"strings"
+	"time"
 )
 
 // errorInterface is the error interface.
 var errorInterface = reflect.TypeOf((*error)(nil)).Elem()
 
+// errorSliceType is the []error type.
+var errorSliceType = reflect.TypeOf([]error(nil))
+
+// multiUnwrapperInterface is the interface of errors wrapping multiple errors.
+var multiUnwrapperInterface = reflect.TypeOf((*interface{ Unwrap() []error })(nil)).Elem()
+
 // contextInterface is the context interface.
 var contextInterface = reflect.TypeOf((*context.Context)(nil)).Elem()
 
+// rawMessageType is the json.RawMessage type.
+var rawMessageType = reflect.TypeOf(json.RawMessage{})
+
+// unmarshalerInterface is the json.Unmarshaler interface.
+var unmarshalerInterface = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// textUnmarshalerInterface is the encoding.TextUnmarshaler interface.
+var textUnmarshalerInterface = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// writerInterface is the io.Writer interface.
+var writerInterface = reflect.TypeOf((*io.Writer)(nil)).Elem()
+
+// closerInterface is the io.Closer interface.
+var closerInterface = reflect.TypeOf((*io.Closer)(nil)).Elem()
+
+// bigIntType is the big.Int type.
+var bigIntType = reflect.TypeOf(big.Int{})
+
+// bigFloatType is the big.Float type.
+var bigFloatType = reflect.TypeOf(big.Float{})
+
+// durationType is the time.Duration type.
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// urlType is the url.URL type.
+var urlType = reflect.TypeOf(url.URL{})
+
 // typeName returns the JSON name of the corresponding Go type.
 func typeName(t reflect.Type) string {
+	if unrollPointer(t) == rawMessageType {
+		return "any JSON"
+	}
+
+	if isBigNumber(t) {
+		return "number"
+	}
+
+	if unrollPointer(t) == durationType {
+		return "duration string or number"
+	}
+
+	if isUnmarshaler(t) {
+		return "value"
+	}
+
+	if isTextUnmarshaler(t) || unrollPointer(t) == urlType {
+		return "string"
+	}
+
 	switch unrollPointer(t).Kind() {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
 		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
 		reflect.Float32, reflect.Float64:
 		return "number"
 	case reflect.Slice, reflect.Array:
-		return "array of " + typeName(t.Elem()) + "s"
+		return "array of " + typeName(unrollPointer(t).Elem()) + "s"
 	case reflect.Bool:
 		return "boolean"
 	case reflect.String:
@@ -41,13 +104,77 @@ func unrollPointer(t reflect.Type) reflect.Type {
 	return t
 }
 
-// hasContext returns true if the function type has a context argument at the given index.
-func hasContext(t reflect.Type, i int) bool {
-	if t.NumIn() < i+1 {
-		return false
+// paramOffset returns the index of the first non-receiver parameter.
+func paramOffset(isMethod bool) int {
+	if isMethod {
+		return 1
+	}
+	return 0
+}
+
+// isUnmarshaler returns true if the given type implements json.Unmarshaler.
+func isUnmarshaler(t reflect.Type) bool {
+	return reflect.PtrTo(unrollPointer(t)).Implements(unmarshalerInterface)
+}
+
+// isTextUnmarshaler returns true if the given type implements
+// encoding.TextUnmarshaler, such as net.IP or netip.Addr.
+func isTextUnmarshaler(t reflect.Type) bool {
+	return reflect.PtrTo(unrollPointer(t)).Implements(textUnmarshalerInterface)
+}
+
+// isBigNumber returns true if the given type is a big.Int or big.Float.
+func isBigNumber(t reflect.Type) bool {
+	t = unrollPointer(t)
+	return t == bigIntType || t == bigFloatType
+}
+
+// bigNumberLiteral adapts a JSON number or string to the form accepted by
+// big.Int, which parses the raw literal, or big.Float, which only implements
+// encoding.TextUnmarshaler and so rejects numbers.
+func bigNumberLiteral(t reflect.Type, raw json.RawMessage) json.RawMessage {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return raw
+	}
+
+	isString := raw[0] == '"'
+	isNumber := raw[0] == '-' || (raw[0] >= '0' && raw[0] <= '9')
+
+	switch {
+	case unrollPointer(t) == bigIntType && isString:
+		var s string
+		if json.Unmarshal(raw, &s) == nil {
+			return json.RawMessage(s)
+		}
+	case unrollPointer(t) == bigFloatType && isNumber:
+		return append(append(json.RawMessage{'"'}, raw...), '"')
 	}
 
-	return isContext(t.In(i))
+	return raw
+}
+
+// jsonKind returns the kind of a JSON value as named by json.UnmarshalTypeError.
+func jsonKind(raw json.RawMessage) string {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return "value"
+	}
+
+	switch raw[0] {
+	case '"':
+		return "string"
+	case '{':
+		return "object"
+	case '[':
+		return "array"
+	case 't', 'f':
+		return "bool"
+	case 'n':
+		return "null"
+	default:
+		return "number"
+	}
 }
 
 // isContext returns true if the given type implements context.Context.
@@ -55,7 +182,416 @@ func isContext(t reflect.Type) bool {
 	return t.Kind() == reflect.Interface && t.Implements(contextInterface)
 }
 
-// isError returns true if the given type implements error.
+// isError returns true if the given type implements error, including
+// concrete types such as *MyError as well as the error interface.
 func isError(t reflect.Type) bool {
-	return t.Kind() == reflect.Interface && t.Implements(errorInterface)
+	return t.Implements(errorInterface)
+}
+
+// hasError returns true if v is of an error type and holds an error, which
+// is any non-nil value of nilable types such as *MyError, and any non-zero
+// value of others, so that the zero value of a struct error means success.
+func hasError(v reflect.Value) bool {
+	if !v.IsValid() || !isError(v.Type()) {
+		return false
+	}
+
+	if isNilable(v.Type()) {
+		return !v.IsNil()
+	}
+
+	return !v.IsZero()
+}
+
+// isNilable returns true if values of the given type may be nil.
+func isNilable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface, reflect.Func, reflect.Chan:
+		return true
+	default:
+		return false
+	}
+}
+
+// isNull returns true if the raw json value is null.
+func isNull(raw json.RawMessage) bool {
+	return bytes.Equal(bytes.TrimSpace(raw), []byte("null"))
+}
+
+// isArray returns true if the raw json value is an array.
+func isArray(raw json.RawMessage) bool {
+	raw = bytes.TrimSpace(raw)
+	return len(raw) > 0 && raw[0] == '['
+}
+
+// joinParams returns a json array of the given params.
+func joinParams(params []json.RawMessage) json.RawMessage {
+	b := []byte("[")
+	for i, p := range params {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = append(b, p...)
+	}
+	return append(b, ']')
+}
+
+// isStructObject returns true if objects decoding into t are structs, whose
+// keys are field names, rather than maps or custom unmarshalers.
+func isStructObject(t reflect.Type) bool {
+	return t != nil && !isUnmarshaler(t) && unrollPointer(t).Kind() == reflect.Struct
+}
+
+// valueType returns the type the value of key decodes into within an object
+// decoding into t, or nil when unknown.
+func valueType(t reflect.Type, key string) reflect.Type {
+	if t == nil || isUnmarshaler(t) {
+		return nil
+	}
+
+	switch t = unrollPointer(t); t.Kind() {
+	case reflect.Struct:
+		return fieldType(t, key)
+	case reflect.Map:
+		return t.Elem()
+	default:
+		return nil
+	}
+}
+
+// elemType returns the type the elements of an array decoding into t decode
+// into, or nil when unknown.
+func elemType(t reflect.Type) reflect.Type {
+	if t == nil || isUnmarshaler(t) {
+		return nil
+	}
+
+	switch t = unrollPointer(t); t.Kind() {
+	case reflect.Slice, reflect.Array:
+		return t.Elem()
+	default:
+		return nil
+	}
+}
+
+// fieldType returns the type of the field of the struct type t which
+// encoding/json decodes key into, preferring an exact match to a
+// case-insensitive one, or nil when there is none.
+func fieldType(t reflect.Type, key string) reflect.Type {
+	if f, ok := findField(t, key, false); ok {
+		return f
+	}
+	f, _ := findField(t, key, true)
+	return f
+}
+
+// findField returns the type of the field of the struct type t named key,
+// including those promoted from embedded structs.
+func findField(t reflect.Type, key string, fold bool) (reflect.Type, bool) {
+	var promoted []reflect.Type
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		if isPromoted(f) {
+			promoted = append(promoted, unrollPointer(f.Type))
+			continue
+		}
+
+		if f.PkgPath != "" || f.Tag.Get("json") == "-" {
+			continue
+		}
+
+		if name := fieldName(f); name == key || fold && strings.EqualFold(name, key) {
+			return f.Type, true
+		}
+	}
+
+	for _, p := range promoted {
+		if f, ok := findField(p, key, fold); ok {
+			return f, true
+		}
+	}
+
+	return nil, false
+}
+
+// isStructLike returns true if the given type is a struct, or a pointer,
+// slice or array of structs.
+func isStructLike(t reflect.Type) bool {
+	for {
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array:
+			t = t.Elem()
+		case reflect.Struct:
+			return true
+		default:
+			return false
+		}
+	}
+}
+
+// isSliceMap returns true if the given type is a map of strings to non-byte slices.
+func isSliceMap(t reflect.Type) bool {
+	t = unrollPointer(t)
+	if t.Kind() != reflect.Map || t.Key().Kind() != reflect.String {
+		return false
+	}
+	e := t.Elem()
+	return e.Kind() == reflect.Slice && e.Elem().Kind() != reflect.Uint8
+}
+
+// isFloat returns true if the given type is a float or pointer to one.
+func isFloat(t reflect.Type) bool {
+	switch unrollPointer(t).Kind() {
+	case reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// nonFiniteFloat returns the float for the strings "NaN", "Infinity" and "-Infinity".
+func nonFiniteFloat(raw json.RawMessage) (float64, bool) {
+	switch string(bytes.TrimSpace(raw)) {
+	case `"NaN"`:
+		return math.NaN(), true
+	case `"Infinity"`:
+		return math.Inf(1), true
+	case `"-Infinity"`:
+		return math.Inf(-1), true
+	default:
+		return 0, false
+	}
+}
+
+// nonFiniteString returns the string for NaN and infinite floats.
+func nonFiniteString(f float64) (string, bool) {
+	switch {
+	case math.IsNaN(f):
+		return "NaN", true
+	case math.IsInf(f, 1):
+		return "Infinity", true
+	case math.IsInf(f, -1):
+		return "-Infinity", true
+	default:
+		return "", false
+	}
+}
+
+// allocate allocates any pointers of v, returning the value pointed to.
+func allocate(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr {
+		v.Set(reflect.New(v.Type().Elem()))
+		v = v.Elem()
+	}
+	return v
+}
+
+// isUnsupported returns true if values of the given type can't be decoded
+// from JSON, such as channels and functions which don't implement an
+// unmarshaler.
+func isUnsupported(t reflect.Type) bool {
+	if isUnmarshaler(t) || isTextUnmarshaler(t) {
+		return false
+	}
+
+	switch unrollPointer(t).Kind() {
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return true
+	default:
+		return false
+	}
+}
+
+// positionalFields returns the indices of the exported fields of a struct
+// type in declaration order, excluding those tagged json:"-".
+func positionalFields(t reflect.Type) []int {
+	var fields []int
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" || f.Tag.Get("json") == "-" {
+			continue
+		}
+		fields = append(fields, i)
+	}
+	return fields
+}
+
+// isInteger returns true if the given type is an integer or pointer to one.
+func isInteger(t reflect.Type) bool {
+	switch unrollPointer(t).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	default:
+		return false
+	}
+}
+
+// integerLiteral rewrites a JSON number with a fraction or exponent as an
+// integer literal, or returns an *IntegerError when it is not whole. Numbers
+// too large for any integer are returned unchanged for the decoder to report.
+func integerLiteral(raw json.RawMessage) (json.RawMessage, error) {
+	raw = bytes.TrimSpace(raw)
+	if !bytes.ContainsAny(raw, ".eE") {
+		return raw, nil
+	}
+
+	f, _, err := big.ParseFloat(string(raw), 10, 1024, big.ToNearestEven)
+	if err != nil || f.MantExp(nil) > 64 {
+		return raw, nil
+	}
+
+	if !f.IsInt() {
+		return nil, &IntegerError{Value: string(raw)}
+	}
+
+	n, _ := f.Int(nil)
+	return json.RawMessage(n.String()), nil
+}
+
+// rangeError returns a *RangeError when the decoder reports a whole number,
+// such as 300 or 1e3, which overflows an integer type at any depth, or nil.
+func rangeError(e *json.UnmarshalTypeError) error {
+	if e.Type == nil || !isInteger(e.Type) || !strings.HasPrefix(e.Value, "number ") {
+		return nil
+	}
+
+	s := strings.TrimPrefix(e.Value, "number ")
+	f, _, err := big.ParseFloat(s, 10, 1024, big.ToNearestEven)
+	if err != nil || !f.IsInt() {
+		return nil
+	}
+
+	// too large for any integer type
+	if f.MantExp(nil) > 64 {
+		return &RangeError{Value: s, Kind: e.Type.Kind(), Field: e.Field}
+	}
+
+	n, _ := f.Int(nil)
+	if fitsInteger(e.Type, n) {
+		return nil
+	}
+
+	return &RangeError{Value: n.String(), Kind: e.Type.Kind(), Field: e.Field}
+}
+
+// typeError returns the error for a type mismatch reported by the decoder,
+// which is a *RangeError for integers out of range and an UnmarshalError
+// otherwise.
+func typeError(e *json.UnmarshalTypeError) error {
+	if err := rangeError(e); err != nil {
+		return err
+	}
+	return UnmarshalError(*e)
+}
+
+// fitsInteger returns true if n is within the range of the integer type t.
+func fitsInteger(t reflect.Type, n *big.Int) bool {
+	v := reflect.Zero(t)
+	switch t.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return n.Sign() >= 0 && n.IsUint64() && !v.OverflowUint(n.Uint64())
+	default:
+		return n.IsInt64() && !v.OverflowInt(n.Int64())
+	}
+}
+
+// isElementType returns true if the given type is a non-byte slice or array.
+func isElementType(t reflect.Type) bool {
+	t = unrollPointer(t)
+	return (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && t.Elem().Kind() != reflect.Uint8
+}
+
+// isCoercibleSlice returns true if the given type is a non-byte slice.
+func isCoercibleSlice(t reflect.Type) bool {
+	t = unrollPointer(t)
+	return t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8
+}
+
+// wrapScalarMapValues wraps the non-array values of a JSON object in arrays. The
+// input is returned unchanged when it is not an object, so that the decode
+// reports the original error.
+func wrapScalarMapValues(raw json.RawMessage) json.RawMessage {
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &m); err != nil || m == nil {
+		return raw
+	}
+
+	for k, v := range m {
+		v = bytes.TrimSpace(v)
+		if len(v) > 0 && v[0] != '[' && !isNull(v) {
+			m[k] = append(append([]byte("["), v...), ']')
+		}
+	}
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		return raw
+	}
+
+	return b
+}
+
+// blankAsEmpty returns an empty json array when s is blank, so that blank input
+// is treated as no params by every entry point, as Normalize does.
+func blankAsEmpty(s string) string {
+	if strings.TrimSpace(s) == "" {
+		return "[]"
+	}
+	return s
+}
+
+// isEmptyArray returns true if s is empty or an empty json array.
+func isEmptyArray(s string) bool {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return true
+	}
+	n := len(s)
+	return n >= 2 && s[0] == '[' && s[n-1] == ']' && strings.TrimSpace(s[1:n-1]) == ""
+}
+
+// jsonDepth returns the maximum nesting depth of arrays and objects in s,
+// scanning bytes rather than tokens so that it is cheap to run before
+// decoding. Malformed input is left for the decoder to report.
+func jsonDepth(s string) int {
+	depth, max := 0, 0
+	inString := false
+
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+
+		if inString {
+			switch ch {
+			case '\\':
+				i++
+			case '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch ch {
+		case '"':
+			inString = true
+		case '[', '{':
+			depth++
+			if depth > max {
+				max = depth
+			}
+		case ']', '}':
+			depth--
+		}
+	}
+
+	return max
+}
+
+// funcName returns the fully-qualified name of a function value.
+func funcName(fn reflect.Value) string {
+	if f := runtime.FuncForPC(fn.Pointer()); f != nil {
+		return f.Name()
+	}
+	return ""
 }
@@ -1,12 +1,24 @@
 package jsoncall
 
 import (
+	"database/sql"
+	"encoding/json"
+	"math/big"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/tj/assert"
 )
 
+type nullString struct {
+	sql.NullString
+}
+
+func (n *nullString) UnmarshalJSON(b []byte) error {
+	return nil
+}
+
 // Test type name conversion.
 func TestTypeName(t *testing.T) {
 	cases := []struct {
@@ -15,13 +27,31 @@ func TestTypeName(t *testing.T) {
 	}{
 		{1, "number"},
 		{1.5, "number"},
+		{big.Int{}, "number"},
+		{&big.Float{}, "number"},
+		{time.Second, "duration string or number"},
 		{"hello", "string"},
 		{true, "boolean"},
 		{struct{}{}, "object"},
+		{struct{ sql.NullString }{}, "object"},
 		{map[string]string{}, "object"},
 		{[]string{}, "array of strings"},
 		{[]bool{}, "array of booleans"},
 		{[]int{}, "array of numbers"},
+		{[]*struct{}{}, "array of objects"},
+		{new(*struct{}), "object"},
+		{new(int), "number"},
+		{new(*float64), "number"},
+		{new(string), "string"},
+		{new(bool), "boolean"},
+		{&[]int{}, "array of numbers"},
+		{[]*string{}, "array of strings"},
+		{sql.NullString{}, "object"},
+		{nullString{}, "value"},
+		{&nullString{}, "value"},
+		{[]nullString{}, "array of values"},
+		{json.RawMessage{}, "any JSON"},
+		{&json.RawMessage{}, "any JSON"},
 	}
 
 	for _, c := range cases {
@@ -31,3 +61,70 @@ func TestTypeName(t *testing.T) {
 		})
 	}
 }
+
+// Test error type detection.
+func TestIsError(t *testing.T) {
+	assert.True(t, isError(errorInterface))
+	assert.True(t, isError(reflect.TypeOf(&json.SyntaxError{})))
+	assert.False(t, isError(reflect.TypeOf(json.SyntaxError{})))
+	assert.False(t, isError(reflect.TypeOf("")))
+
+	assert.True(t, hasError(reflect.ValueOf(&json.SyntaxError{})))
+	assert.False(t, hasError(reflect.ValueOf((*json.SyntaxError)(nil))))
+	assert.False(t, hasError(reflect.Zero(errorInterface)))
+	assert.False(t, hasError(reflect.ValueOf("")))
+}
+
+// Test struct field lookup by JSON key.
+func TestFieldType(t *testing.T) {
+	type Base struct {
+		ID int `json:"id"`
+	}
+
+	type User struct {
+		Base
+		Name   string
+		name   bool
+		Email  string            `json:"-"`
+		Labels map[string]string `json:"labels,omitempty"`
+	}
+
+	u := reflect.TypeOf(User{})
+	assert.Equal(t, reflect.TypeOf(""), fieldType(u, "Name"))
+	assert.Equal(t, reflect.TypeOf(""), fieldType(u, "NAME"))
+	assert.Equal(t, reflect.TypeOf(0), fieldType(u, "id"))
+	assert.Equal(t, reflect.TypeOf(map[string]string{}), fieldType(u, "labels"))
+	assert.Nil(t, fieldType(u, "Email"))
+	assert.Nil(t, fieldType(u, "Base"))
+	assert.Nil(t, fieldType(u, "unknown"))
+}
+
+// Test empty array detection.
+func TestIsEmptyArray(t *testing.T) {
+	assert.True(t, isEmptyArray(``))
+	assert.True(t, isEmptyArray(`  `))
+	assert.True(t, isEmptyArray(`[]`))
+	assert.True(t, isEmptyArray(` [  ] `))
+	assert.False(t, isEmptyArray(`[1]`))
+	assert.False(t, isEmptyArray(`[`))
+	assert.False(t, isEmptyArray(`null`))
+}
+
+// Test nesting depth scanning.
+func TestJSONDepth(t *testing.T) {
+	cases := []struct {
+		input  string
+		output int
+	}{
+		{``, 0},
+		{`1`, 0},
+		{`[]`, 1},
+		{`[1, {"a": [2]}]`, 3},
+		{`[[1], [2], [3]]`, 2},
+		{`["[[[", "\\\"{{"]`, 1},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.output, jsonDepth(c.input), c.input)
+	}
+}
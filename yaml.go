@@ -0,0 +1,479 @@
+package jsoncall
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// ErrInvalidYAML is returned when YAML input is malformed or uses unsupported syntax.
+var ErrInvalidYAML error = newCallError(http.StatusBadRequest, "invalid_yaml", "Invalid YAML")
+
+// ArgumentsOfFuncYAML returns arguments for a function from a YAML sequence.
+// The YAML is converted to JSON and decoded exactly as ArgumentsOfFunc
+// would, so type and arity errors are identical.
+//
+// A practical subset of YAML is supported: block and flow sequences and
+// mappings, plain and quoted scalars, and comments. Block scalars, anchors,
+// aliases and tags are rejected with ErrInvalidYAML.
+func ArgumentsOfFuncYAML(t reflect.Type, args string, options ...Option) ([]reflect.Value, error) {
+	if t == nil || t.Kind() != reflect.Func {
+		return nil, ErrNotFunction
+	}
+
+	b, err := yamlToJSON(args)
+	if err != nil {
+		return nil, err
+	}
+
+	c := newConfig(options)
+	return arguments(t, string(b), c)
+}
+
+// yamlLine is a non-empty line of YAML with its indentation.
+type yamlLine struct {
+	indent int
+	text   string
+}
+
+// yamlParser converts block-style YAML to JSON.
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+// yamlToJSON converts a YAML document to JSON.
+func yamlToJSON(s string) ([]byte, error) {
+	p := &yamlParser{lines: yamlLines(s)}
+	if len(p.lines) == 0 {
+		return []byte("null"), nil
+	}
+
+	var buf bytes.Buffer
+	if err := p.node(&buf); err != nil {
+		return nil, err
+	}
+
+	if p.pos < len(p.lines) {
+		return nil, ErrInvalidYAML
+	}
+
+	return buf.Bytes(), nil
+}
+
+// yamlLines returns the lines of s with comments, blank lines and document
+// markers removed.
+func yamlLines(s string) (lines []yamlLine) {
+	for _, l := range strings.Split(s, "\n") {
+		l = strings.TrimRight(stripYAMLComment(l), " \t\r")
+		text := strings.TrimLeft(l, " ")
+		if text == "" || text == "---" {
+			continue
+		}
+		lines = append(lines, yamlLine{indent: len(l) - len(text), text: text})
+	}
+	return
+}
+
+// stripYAMLComment removes a trailing comment from a line, ignoring any
+// within quoted scalars.
+func stripYAMLComment(s string) string {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+
+		if quote != 0 {
+			switch {
+			case ch == '\\' && quote == '"':
+				i++
+			case ch == '\'' && quote == '\'' && i+1 < len(s) && s[i+1] == '\'':
+				i++
+			case ch == quote:
+				quote = 0
+			}
+			continue
+		}
+
+		startsToken := i == 0 || strings.IndexByte(" \t[{,:", s[i-1]) >= 0
+
+		switch {
+		case (ch == '"' || ch == '\'') && startsToken:
+			quote = ch
+		case ch == '#' && (i == 0 || s[i-1] == ' ' || s[i-1] == '\t'):
+			return s[:i]
+		}
+	}
+	return s
+}
+
+// node converts the node starting at the current line.
+func (p *yamlParser) node(buf *bytes.Buffer) error {
+	l := p.lines[p.pos]
+	switch {
+	case isYAMLSequenceItem(l.text):
+		return p.sequence(buf, l.indent)
+	case yamlKey(l.text) >= 0:
+		return p.mapping(buf, l.indent)
+	default:
+		p.pos++
+		return yamlValue(buf, l.text)
+	}
+}
+
+// child converts the nested value of an item or key with no inline value,
+// writing null when there is none. Keys may be followed by a sequence at
+// their own indentation.
+func (p *yamlParser) child(buf *bytes.Buffer, indent int, sequenceOK bool) error {
+	if p.pos < len(p.lines) {
+		l := p.lines[p.pos]
+		if l.indent > indent || (sequenceOK && l.indent == indent && isYAMLSequenceItem(l.text)) {
+			return p.node(buf)
+		}
+	}
+	buf.WriteString("null")
+	return nil
+}
+
+// sequence converts a block sequence.
+func (p *yamlParser) sequence(buf *bytes.Buffer, indent int) error {
+	buf.WriteByte('[')
+	for n := 0; p.pos < len(p.lines); n++ {
+		l := p.lines[p.pos]
+		if l.indent != indent || !isYAMLSequenceItem(l.text) {
+			break
+		}
+
+		if n > 0 {
+			buf.WriteByte(',')
+		}
+
+		rest := strings.TrimLeft(l.text[1:], " ")
+
+		if rest == "" {
+			p.pos++
+			if err := p.child(buf, indent, false); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// treat the content as a line of its own, indented past the dash
+		p.lines[p.pos] = yamlLine{indent: indent + len(l.text) - len(rest), text: rest}
+		if err := p.node(buf); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(']')
+	return p.dedented(indent)
+}
+
+// mapping converts a block mapping.
+func (p *yamlParser) mapping(buf *bytes.Buffer, indent int) error {
+	buf.WriteByte('{')
+	for n := 0; p.pos < len(p.lines); n++ {
+		l := p.lines[p.pos]
+		if l.indent != indent {
+			break
+		}
+
+		i := yamlKey(l.text)
+		if i < 0 || isYAMLSequenceItem(l.text) {
+			return ErrInvalidYAML
+		}
+
+		if n > 0 {
+			buf.WriteByte(',')
+		}
+
+		if err := yamlString(buf, strings.TrimSpace(l.text[:i])); err != nil {
+			return err
+		}
+		buf.WriteByte(':')
+
+		rest := strings.TrimSpace(l.text[i+1:])
+		p.pos++
+
+		var err error
+		if rest == "" {
+			err = p.child(buf, indent, true)
+		} else {
+			err = yamlValue(buf, rest)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+	buf.WriteByte('}')
+	return p.dedented(indent)
+}
+
+// dedented returns an error if the next line is indented further than a
+// node which has ended.
+func (p *yamlParser) dedented(indent int) error {
+	if p.pos < len(p.lines) && p.lines[p.pos].indent > indent {
+		return ErrInvalidYAML
+	}
+	return nil
+}
+
+// isYAMLSequenceItem returns true if the line is a block sequence item.
+func isYAMLSequenceItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// yamlKey returns the index of the colon following a mapping key, or -1.
+func yamlKey(text string) int {
+	if text == "" || text[0] == '[' || text[0] == '{' {
+		return -1
+	}
+
+	isSep := func(i int) bool {
+		return text[i] == ':' && (i+1 == len(text) || text[i+1] == ' ' || text[i+1] == '\t')
+	}
+
+	if text[0] == '"' || text[0] == '\'' {
+		f := &yamlFlow{s: text}
+		if _, err := f.quoted(); err != nil {
+			return -1
+		}
+		f.space()
+		if f.i < len(text) && isSep(f.i) {
+			return f.i
+		}
+		return -1
+	}
+
+	for i := range text {
+		if isSep(i) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// yamlValue converts an inline value, which is either a flow collection,
+// a quoted scalar or a plain scalar.
+func yamlValue(buf *bytes.Buffer, text string) error {
+	switch text[0] {
+	case '[', '{', '"', '\'':
+		f := &yamlFlow{s: text}
+		if err := f.value(buf); err != nil {
+			return err
+		}
+		f.space()
+		if f.i < len(f.s) {
+			return ErrInvalidYAML
+		}
+		return nil
+	case '|', '>', '&', '*', '!', '%', '@', '`':
+		return ErrInvalidYAML
+	default:
+		return yamlScalar(buf, text)
+	}
+}
+
+// yamlString writes a mapping key as a JSON string.
+func yamlString(buf *bytes.Buffer, text string) error {
+	if text != "" && (text[0] == '"' || text[0] == '\'') {
+		f := &yamlFlow{s: text}
+		s, err := f.quoted()
+		if err != nil {
+			return err
+		}
+		text = s
+	}
+
+	b, _ := json.Marshal(text)
+	buf.Write(b)
+	return nil
+}
+
+// yamlScalar writes a plain scalar as JSON, resolving nulls, booleans and
+// numbers.
+func yamlScalar(buf *bytes.Buffer, text string) error {
+	switch text {
+	case "", "~", "null", "Null", "NULL":
+		buf.WriteString("null")
+		return nil
+	case "true", "True", "TRUE":
+		buf.WriteString("true")
+		return nil
+	case "false", "False", "FALSE":
+		buf.WriteString("false")
+		return nil
+	}
+
+	if (text[0] == '-' || (text[0] >= '0' && text[0] <= '9')) && json.Valid([]byte(text)) {
+		buf.WriteString(text)
+		return nil
+	}
+
+	b, _ := json.Marshal(text)
+	buf.Write(b)
+	return nil
+}
+
+// yamlFlow converts flow-style YAML to JSON.
+type yamlFlow struct {
+	s string
+	i int
+}
+
+// space skips whitespace.
+func (f *yamlFlow) space() {
+	for f.i < len(f.s) && (f.s[f.i] == ' ' || f.s[f.i] == '\t') {
+		f.i++
+	}
+}
+
+// value converts a flow value.
+func (f *yamlFlow) value(buf *bytes.Buffer) error {
+	f.space()
+	if f.i >= len(f.s) {
+		return ErrInvalidYAML
+	}
+
+	switch f.s[f.i] {
+	case '[':
+		return f.collection(buf, '[', ']', false)
+	case '{':
+		return f.collection(buf, '{', '}', true)
+	case '"', '\'':
+		s, err := f.quoted()
+		if err != nil {
+			return err
+		}
+		b, _ := json.Marshal(s)
+		buf.Write(b)
+		return nil
+	case '|', '>', '&', '*', '!', '%', '@', '`', ']', '}', ',', ':':
+		return ErrInvalidYAML
+	default:
+		return yamlScalar(buf, f.plain(",[]{}"))
+	}
+}
+
+// collection converts a flow sequence or mapping.
+func (f *yamlFlow) collection(buf *bytes.Buffer, open, close byte, mapping bool) error {
+	f.i++
+	buf.WriteByte(open)
+	for n := 0; ; n++ {
+		f.space()
+		if f.i >= len(f.s) {
+			return ErrInvalidYAML
+		}
+
+		if f.s[f.i] == close {
+			f.i++
+			break
+		}
+
+		if n > 0 {
+			if f.s[f.i] != ',' {
+				return ErrInvalidYAML
+			}
+			f.i++
+			f.space()
+
+			// trailing comma
+			if f.i < len(f.s) && f.s[f.i] == close {
+				f.i++
+				break
+			}
+
+			buf.WriteByte(',')
+		}
+
+		if mapping {
+			if err := f.key(buf); err != nil {
+				return err
+			}
+			buf.WriteByte(':')
+		}
+
+		if err := f.value(buf); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(close)
+	return nil
+}
+
+// key converts a flow mapping key and its colon.
+func (f *yamlFlow) key(buf *bytes.Buffer) error {
+	f.space()
+	if f.i >= len(f.s) {
+		return ErrInvalidYAML
+	}
+
+	var key string
+	if c := f.s[f.i]; c == '"' || c == '\'' {
+		s, err := f.quoted()
+		if err != nil {
+			return err
+		}
+		key = s
+	} else {
+		key = f.plain(":,[]{}")
+	}
+
+	f.space()
+	if f.i >= len(f.s) || f.s[f.i] != ':' {
+		return ErrInvalidYAML
+	}
+	f.i++
+
+	b, _ := json.Marshal(key)
+	buf.Write(b)
+	return nil
+}
+
+// plain returns a plain scalar terminated by any of the stop characters.
+func (f *yamlFlow) plain(stop string) string {
+	start := f.i
+	for f.i < len(f.s) && strings.IndexByte(stop, f.s[f.i]) < 0 {
+		f.i++
+	}
+	return strings.TrimSpace(f.s[start:f.i])
+}
+
+// quoted returns the contents of a single or double quoted scalar.
+func (f *yamlFlow) quoted() (string, error) {
+	quote := f.s[f.i]
+	start := f.i
+	f.i++
+
+	for f.i < len(f.s) {
+		ch := f.s[f.i]
+
+		switch {
+		case ch == '\\' && quote == '"':
+			f.i += 2
+			continue
+		case ch == '\'' && quote == '\'' && f.i+1 < len(f.s) && f.s[f.i+1] == '\'':
+			f.i += 2
+			continue
+		case ch == quote:
+			f.i++
+			text := f.s[start:f.i]
+
+			if quote == '\'' {
+				return strings.ReplaceAll(text[1:len(text)-1], "''", "'"), nil
+			}
+
+			var s string
+			if err := json.Unmarshal([]byte(text), &s); err != nil {
+				return "", ErrInvalidYAML
+			}
+			return s, nil
+		}
+
+		f.i++
+	}
+
+	return "", ErrInvalidYAML
+}
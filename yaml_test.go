@@ -0,0 +1,120 @@
+package jsoncall_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/tj/assert"
+	jsoncall "github.com/tj/go-jsoncall"
+)
+
+// Test argument decoding from YAML.
+func TestArgumentsOfFuncYAML(t *testing.T) {
+	t.Run("should decode block sequences", func(t *testing.T) {
+		args, err := jsoncall.ArgumentsOfFuncYAML(reflect.TypeOf(add), "- 1\n- 2\n")
+		assert.NoError(t, err)
+		assert.Len(t, args, 2)
+		assert.Equal(t, 1, args[0].Interface())
+		assert.Equal(t, 2, args[1].Interface())
+	})
+
+	t.Run("should decode flow sequences", func(t *testing.T) {
+		args, err := jsoncall.ArgumentsOfFuncYAML(reflect.TypeOf(add), `[1, 2]`)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, args[0].Interface())
+		assert.Equal(t, 2, args[1].Interface())
+	})
+
+	t.Run("should decode mappings into structs", func(t *testing.T) {
+		yaml := `
+# the user to add
+- name: Tobi
+  email: "tobi@apex.sh"  # quoted
+`
+		args, err := jsoncall.ArgumentsOfFuncYAML(reflect.TypeOf(addUser), yaml)
+		assert.NoError(t, err)
+		assert.Equal(t, User{Name: "Tobi", Email: "tobi@apex.sh"}, args[0].Interface())
+	})
+
+	t.Run("should decode nested collections", func(t *testing.T) {
+		yaml := `
+- - name: Tobi
+  - {name: Loki, email: 'loki''s@apex.sh'}
+`
+		args, err := jsoncall.ArgumentsOfFuncYAML(reflect.TypeOf(addUsers), yaml)
+		assert.NoError(t, err)
+		assert.Equal(t, []User{{Name: "Tobi"}, {Name: "Loki", Email: "loki's@apex.sh"}}, args[0].Interface())
+	})
+
+	t.Run("should resolve scalars", func(t *testing.T) {
+		fn := func(v interface{}) {}
+
+		cases := []struct {
+			yaml   string
+			output interface{}
+		}{
+			{`- ~`, nil},
+			{`- null`, nil},
+			{`- true`, true},
+			{`- False`, false},
+			{`- -1.5e3`, -1500.0},
+			{`- 01`, "01"},
+			{`- hello, world`, "hello, world"},
+			{`- http://apex.sh`, "http://apex.sh"},
+			{`- "a # b"`, "a # b"},
+			{"- tags:\n  - a\n  - b", map[string]interface{}{"tags": []interface{}{"a", "b"}}},
+			{"- tags:\n  - a\n  empty:", map[string]interface{}{"tags": []interface{}{"a"}, "empty": nil}},
+			{`- {a: [1, 2,], "b c": x}`, map[string]interface{}{"a": []interface{}{1.0, 2.0}, "b c": "x"}},
+		}
+
+		for _, c := range cases {
+			t.Run(c.yaml, func(t *testing.T) {
+				args, err := jsoncall.ArgumentsOfFuncYAML(reflect.TypeOf(fn), c.yaml)
+				assert.NoError(t, err)
+				assert.Equal(t, c.output, args[0].Interface())
+			})
+		}
+	})
+
+	t.Run("should match JSON errors for incorrect types", func(t *testing.T) {
+		_, jsonErr := jsoncall.ArgumentsOfFunc(reflect.TypeOf(add), `["1", 2]`)
+		_, err := jsoncall.ArgumentsOfFuncYAML(reflect.TypeOf(add), "- \"1\"\n- 2")
+		assert.Error(t, err)
+		assert.Equal(t, jsonErr, err)
+	})
+
+	t.Run("should match JSON errors for arity", func(t *testing.T) {
+		_, err := jsoncall.ArgumentsOfFuncYAML(reflect.TypeOf(add), "- 1")
+		assert.EqualError(t, err, `Too few arguments: expected 2, got 1`)
+	})
+
+	t.Run("should error when not a sequence", func(t *testing.T) {
+		_, err := jsoncall.ArgumentsOfFuncYAML(reflect.TypeOf(addUser), "name: Tobi")
+		assert.True(t, errors.Is(err, jsoncall.ErrExpectedArray))
+	})
+
+	t.Run("should error on invalid YAML", func(t *testing.T) {
+		cases := []string{
+			`[1, 2`,
+			`- "unterminated`,
+			"- a\n  - b",
+			"- |\n  text",
+			"- &anchor 1",
+			"name: Tobi\n- 1",
+		}
+
+		for _, yaml := range cases {
+			_, err := jsoncall.ArgumentsOfFuncYAML(reflect.TypeOf(add), yaml)
+			assert.Equal(t, jsoncall.ErrInvalidYAML, err, yaml)
+		}
+	})
+
+	t.Run("should error when not a function", func(t *testing.T) {
+		_, err := jsoncall.ArgumentsOfFuncYAML(reflect.TypeOf(1), "- 1")
+		assert.Equal(t, jsoncall.ErrNotFunction, err)
+
+		_, err = jsoncall.ArgumentsOfFuncYAML(nil, "- 1")
+		assert.Equal(t, jsoncall.ErrNotFunction, err)
+	})
+}